@@ -1,13 +1,20 @@
 package builder
 
 import (
+	"errors"
 	"fmt"
+	"path"
 	"path/filepath"
 	"strings"
 
+	"github.com/janmarkuslanger/ssgo/data"
+	"github.com/janmarkuslanger/ssgo/i18n"
 	"github.com/janmarkuslanger/ssgo/page"
+	"github.com/janmarkuslanger/ssgo/redirect"
 	"github.com/janmarkuslanger/ssgo/rendering"
 	"github.com/janmarkuslanger/ssgo/task"
+	"github.com/janmarkuslanger/ssgo/toc"
+	"github.com/janmarkuslanger/ssgo/urls"
 	"github.com/janmarkuslanger/ssgo/writer"
 )
 
@@ -18,6 +25,60 @@ type Builder struct {
 	Renderer    rendering.Renderer
 	BeforeTasks []task.Task
 	AfterTasks  []task.Task
+	// Data loads the site data once per build and exposes it to GetData and templates.
+	Data *data.Loader
+	// Publish excludes drafts, scheduled and expired pages from the build.
+	Publish page.PublishPolicy
+	// Redirects maps old paths to new URLs. Pages can add their own via an
+	// "aliases" list in their data.
+	Redirects map[string]string
+	// RedirectFiles are server configs written alongside the redirect pages,
+	// e.g. redirect.Netlify, redirect.Nginx or redirect.Apache.
+	RedirectFiles []redirect.File
+	// URLs sets the site-wide URL style used for output files and links.
+	URLs urls.Config
+	// BaseURL is the absolute site root, e.g. https://example.com/docs/.
+	BaseURL string
+	// RewriteURLs prefixes root-relative href and src attributes in HTML
+	// output with the path of BaseURL.
+	RewriteURLs bool
+	// Transforms post-process the output of every page in order, e.g.
+	// highlight.Transform.
+	Transforms []Transform
+	// Languages makes every generator emit its pages once per language below
+	// /<code>/. The first language is the default.
+	Languages []i18n.Language
+	// Translations backs the T template function.
+	Translations *i18n.Bundle
+}
+
+// Transform rewrites the rendered content of file, the output path relative
+// to OutputDir.
+type Transform func(file, content string) (string, error)
+
+type Report struct {
+	Written   []string
+	Skipped   []SkippedPage
+	Redirects []redirect.Redirect
+	// Templates lists the templates and blocks executed per written file by
+	// renderers in debug mode, e.g. rendering.HTMLRenderer with Debug set.
+	Templates map[string][]string
+	// Warnings are missing map keys found by renderers in debug mode.
+	Warnings []Warning
+	// TOCs holds the table of contents per written file for renderers that
+	// collect headings, e.g. rendering.HTMLRenderer with Headings set.
+	TOCs map[string]toc.TOC
+}
+
+type Warning struct {
+	// Path is the written file.
+	Path    string
+	Message string
+}
+
+type SkippedPage struct {
+	Path   string
+	Reason string
 }
 
 func (b Builder) RunTasks(tasks []task.Task) error {
@@ -38,45 +99,316 @@ func (b Builder) RunTasks(tasks []task.Task) error {
 	return nil
 }
 
+func (b Builder) LoadData() (map[string]any, error) {
+	if b.Data == nil {
+		return nil, nil
+	}
+
+	b.Data.Reset()
+	site, err := b.Data.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load data: %w", err)
+	}
+
+	return site, nil
+}
+
 func (b Builder) Build() error {
+	_, err := b.BuildWithReport()
+	return err
+}
+
+func (b Builder) BuildWithReport() (Report, error) {
+	var report Report
+
 	if err := b.RunTasks(b.BeforeTasks); err != nil {
-		return err
+		return report, err
+	}
+
+	if err := b.ValidateTemplates(); err != nil {
+		return report, err
 	}
 
+	site, err := b.LoadData()
+	if err != nil {
+		return report, err
+	}
+
+	var redirects []redirect.Redirect
+
 	for _, g := range b.Generators {
-		pages, err := g.GeneratePageInstances()
+		for _, lang := range b.GeneratorLanguages(g) {
+			g := b.PrepareGenerator(g, site, lang)
+			pages, err := g.GeneratePageInstances()
+			if err != nil {
+				return report, fmt.Errorf("failed to generate pages: %w", err)
+			}
+
+			for _, p := range pages {
+				cleanPath, err := cleanPagePath(p.Path)
+				if err != nil {
+					return report, err
+				}
+				outPath := filepath.Join(lang.Code, cleanPath)
+
+				if reason := b.Publish.SkipReason(p.Data); reason != "" {
+					report.Skipped = append(report.Skipped, SkippedPage{Path: filepath.ToSlash(outPath), Reason: reason})
+					continue
+				}
+
+				for _, o := range p.Formats() {
+					file := b.URLs.File(outPath, o.Extension)
+					p.Debug = &rendering.Debug{}
+					p.TOC = &toc.TOC{}
+					if err := b.writePage(p, o, file); err != nil {
+						return report, err
+					}
+					report.Written = append(report.Written, file)
+					report.addDebug(file, p.Debug)
+					report.addTOC(file, *p.TOC)
+				}
+
+				for _, alias := range redirect.Aliases(p.Data) {
+					if lang.Code != "" {
+						alias = path.Join(lang.Code, strings.TrimPrefix(alias, "/"))
+					}
+					redirects = append(redirects, redirect.Redirect{From: alias, To: p.URL(p.Formats()[0])})
+				}
+			}
+		}
+	}
+
+	if err := b.writeLanguageRedirect(&report); err != nil {
+		return report, err
+	}
+
+	for from, to := range b.Redirects {
+		redirects = append(redirects, redirect.Redirect{From: from, To: to})
+	}
+
+	if err := b.writeRedirects(redirects, report.Written); err != nil {
+		return report, err
+	}
+	report.Redirects = redirects
+
+	if err := b.RunTasks(b.AfterTasks); err != nil {
+		return report, err
+	}
+
+	return report, nil
+}
+
+func (r *Report) addDebug(file string, debug *rendering.Debug) {
+	if len(debug.Templates) > 0 {
+		if r.Templates == nil {
+			r.Templates = make(map[string][]string)
+		}
+		r.Templates[file] = debug.Templates
+	}
+
+	for _, w := range debug.Warnings {
+		fmt.Printf("warning: %s: %v\n", file, w)
+		r.Warnings = append(r.Warnings, Warning{Path: file, Message: w.Error()})
+	}
+}
+
+func (r *Report) addTOC(file string, contents toc.TOC) {
+	if len(contents) == 0 {
+		return
+	}
+	if r.TOCs == nil {
+		r.TOCs = make(map[string]toc.TOC)
+	}
+	r.TOCs[file] = contents
+}
+
+// writePage streams the page into the writer when it implements
+// writer.StreamWriter and no post-processing needs the full output, and
+// renders to a string otherwise. file is relative to OutputDir.
+func (b Builder) writePage(p page.Page, o page.OutputFormat, file string) error {
+	rewrite := b.RewriteURLs && filepath.Ext(file) == ".html"
+	dest := filepath.Join(b.OutputDir, file)
+
+	if sw, ok := b.Writer.(writer.StreamWriter); ok && !rewrite && len(b.Transforms) == 0 {
+		w, err := sw.Create(dest)
 		if err != nil {
-			return fmt.Errorf("failed to generate pages: %w", err)
+			return fmt.Errorf("failed to write page %s: %w", p.Path, err)
 		}
 
-		for _, p := range pages {
-			cleanPath := filepath.Clean(p.Path)
-			if cleanPath == "." {
-				return fmt.Errorf("page path must not be empty")
+		if err := p.RenderOutputTo(o, w); err != nil {
+			// Keep the previous file instead of a partly rendered page.
+			if a, ok := w.(writer.Aborter); ok {
+				a.Abort()
+			} else {
+				w.Close()
 			}
-			if filepath.IsAbs(cleanPath) {
-				return fmt.Errorf("page path must be relative, got %q", p.Path)
+			return renderFailed(p, err)
+		}
+		if err := w.Close(); err != nil {
+			return fmt.Errorf("failed to write page %s: %w", p.Path, err)
+		}
+		return nil
+	}
+
+	content, err := p.RenderOutput(o)
+	if err != nil {
+		return renderFailed(p, err)
+	}
+	if rewrite {
+		content = urls.RewriteRootRelative(content, b.BaseURL)
+	}
+	content, err = b.ApplyTransforms(file, content)
+	if err != nil {
+		return fmt.Errorf("failed to transform page %s: %w", p.Path, err)
+	}
+
+	if err := b.writeFile(dest, content); err != nil {
+		return fmt.Errorf("failed to write page %s: %w", p.Path, err)
+	}
+	return nil
+}
+
+// writeFile writes file through writer.ExactWriter when the writer
+// implements it, as file already carries its extension.
+func (b Builder) writeFile(file, content string) error {
+	if w, ok := b.Writer.(writer.ExactWriter); ok {
+		return w.WriteExact(file, content)
+	}
+	return b.Writer.Write(file, content)
+}
+
+// ApplyTransforms runs Transforms over the content of file, the output path
+// relative to OutputDir.
+func (b Builder) ApplyTransforms(file, content string) (string, error) {
+	for _, t := range b.Transforms {
+		var err error
+		if content, err = t(file, content); err != nil {
+			return "", err
+		}
+	}
+	return content, nil
+}
+
+func renderFailed(p page.Page, err error) error {
+	// TODO: make configurable if it should continue if single page fails
+	var renderErr *rendering.RenderError
+	if errors.As(err, &renderErr) && renderErr.Detail() != "" {
+		return fmt.Errorf("failed to render page %s: %w\n%s", p.Path, err, renderErr.Detail())
+	}
+	return fmt.Errorf("failed to render page %s: %w", p.Path, err)
+}
+
+// ValidateTemplates parses the templates and layouts of every generator and
+// output format whose renderer implements rendering.TemplateValidator. All
+// problems are reported at once. Templates picked by GetTemplate or
+// renderers picked by GetRenderer are only known per page and not checked.
+func (b Builder) ValidateTemplates() error {
+	var problems []error
+	seen := make(map[string]bool)
+
+	for _, g := range b.Generators {
+		for _, o := range g.Formats() {
+			r := o.Renderer
+			if r == nil {
+				r = g.Config.Renderer
 			}
-			if cleanPath == ".." || strings.HasPrefix(cleanPath, ".."+string(filepath.Separator)) {
-				return fmt.Errorf("page path must not traverse outside output dir: %q", p.Path)
+			v, ok := r.(rendering.TemplateValidator)
+			if !ok {
+				continue
 			}
 
-			content, err := p.Render()
-			if err != nil {
-				// TODO: make configurable if it should continue if single page fails
-				return fmt.Errorf("failed to render page %s: %w", p.Path, err)
+			tmpl := o.Template
+			if tmpl == "" {
+				tmpl = g.Config.Template
 			}
+			for _, err := range v.ValidateTemplates([]string{tmpl}) {
+				if !seen[err.Error()] {
+					seen[err.Error()] = true
+					problems = append(problems, err)
+				}
+			}
+		}
+	}
 
-			fullPath := filepath.Join(b.OutputDir, cleanPath)
-			if err := b.Writer.Write(fullPath, content); err != nil {
-				return fmt.Errorf("failed to write page %s: %w", p.Path, err)
+	if len(problems) > 0 {
+		return fmt.Errorf("template validation failed:\n%w", errors.Join(problems...))
+	}
+	return nil
+}
+
+func cleanPagePath(path string) (string, error) {
+	cleanPath := filepath.Clean(path)
+	if cleanPath == "." {
+		return "", fmt.Errorf("page path must not be empty")
+	}
+	if filepath.IsAbs(cleanPath) {
+		return "", fmt.Errorf("page path must be relative, got %q", path)
+	}
+	if cleanPath == ".." || strings.HasPrefix(cleanPath, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("page path must not traverse outside output dir: %q", path)
+	}
+
+	return cleanPath, nil
+}
+
+// SiteLanguages returns the configured languages with their effective base
+// URL. Without languages it returns a single language without code.
+func (b Builder) SiteLanguages() []i18n.Language {
+	if len(b.Languages) == 0 {
+		return []i18n.Language{{}}
+	}
+
+	langs := make([]i18n.Language, len(b.Languages))
+	for i, l := range b.Languages {
+		if l.BaseURL == "" {
+			l.BaseURL = urls.AbsURL(b.BaseURL, "/"+l.Code+"/")
+		}
+		langs[i] = l
+	}
+	return langs
+}
+
+// GeneratorLanguages returns the languages g runs in: all site languages
+// unless its config lists codes. "" stands for the site root without a
+// language.
+func (b Builder) GeneratorLanguages(g page.Generator) []i18n.Language {
+	if len(b.Languages) == 0 || len(g.Config.Languages) == 0 {
+		return b.SiteLanguages()
+	}
+
+	var langs []i18n.Language
+	for _, code := range g.Config.Languages {
+		if code == "" {
+			langs = append(langs, i18n.Language{})
+			continue
+		}
+		for _, l := range b.SiteLanguages() {
+			if l.Code == code {
+				langs = append(langs, l)
 			}
 		}
 	}
+	return langs
+}
 
-	if err := b.RunTasks(b.AfterTasks); err != nil {
-		return err
+// PrepareGenerator passes the build context to a generator for one language.
+func (b Builder) PrepareGenerator(g page.Generator, site map[string]any, lang i18n.Language) page.Generator {
+	g.Site = site
+	g.Publish = b.Publish
+	g.URLs = b.URLs
+	g.BaseURL = b.BaseURL
+	g.Lang = lang
+
+	if len(b.Languages) > 0 && lang.Code != "" {
+		for _, l := range b.GeneratorLanguages(g) {
+			if l.Code != "" {
+				g.Languages = append(g.Languages, l)
+			}
+		}
+	}
+	if b.Translations != nil {
+		g.Translate = b.Translations.Translator(lang.Code)
 	}
 
-	return nil
+	return g
 }
@@ -2,13 +2,24 @@ package builder_test
 
 import (
 	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/janmarkuslanger/ssgo/builder"
+	"github.com/janmarkuslanger/ssgo/data"
+	"github.com/janmarkuslanger/ssgo/i18n"
 	"github.com/janmarkuslanger/ssgo/page"
+	"github.com/janmarkuslanger/ssgo/redirect"
 	"github.com/janmarkuslanger/ssgo/rendering"
 	"github.com/janmarkuslanger/ssgo/task"
+	"github.com/janmarkuslanger/ssgo/toc"
+	"github.com/janmarkuslanger/ssgo/urls"
+	"github.com/janmarkuslanger/ssgo/writer"
 )
 
 type MockWriter struct{}
@@ -360,3 +371,855 @@ func TestBuilder_Build_FailingRenderer(t *testing.T) {
 		t.Fatal("expected an error but got nil")
 	}
 }
+
+type siteRecorder struct {
+	site map[string]any
+}
+
+func (r *siteRecorder) Render(ctx rendering.RenderContext) (output string, err error) {
+	r.site = ctx.Site
+	return "", nil
+}
+
+func TestBuilder_Build_LoadsData(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "site.json"), []byte(`{"title": "ssgo"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var payloadSite map[string]any
+	r := &siteRecorder{}
+	b := builder.Builder{
+		OutputDir: "/test",
+		Writer:    MockWriter{},
+		Data:      data.NewLoader(dir),
+		Generators: []page.Generator{
+			{
+				Config: page.Config{
+					Renderer: r,
+					GetPaths: func() []string {
+						return []string{"a"}
+					},
+					GetData: func(p page.PagePayload) map[string]any {
+						payloadSite = p.Site
+						return nil
+					},
+				},
+			},
+		},
+	}
+
+	if err := b.Build(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if payloadSite["site"].(map[string]any)["title"] != "ssgo" {
+		t.Errorf("site data missing in payload: %v", payloadSite)
+	}
+	if r.site["site"].(map[string]any)["title"] != "ssgo" {
+		t.Errorf("site data missing in render context: %v", r.site)
+	}
+}
+
+func TestBuilder_Build_FailingData(t *testing.T) {
+	b := builder.Builder{
+		OutputDir: "/test",
+		Writer:    MockWriter{},
+		Data:      data.NewLoader(filepath.Join(t.TempDir(), "missing")),
+	}
+
+	err := b.Build()
+	if err == nil || !strings.HasPrefix(err.Error(), "failed to load data:") {
+		t.Fatalf("expected data error, got %v", err)
+	}
+}
+
+func TestBuilder_BuildWithReport_SkipsUnpublished(t *testing.T) {
+	posts := map[string]map[string]any{
+		"live":      {"title": "live"},
+		"draft":     {"draft": true},
+		"scheduled": {"publishDate": "2030-01-01"},
+	}
+
+	b := builder.Builder{
+		OutputDir: "/test",
+		Writer:    MockWriter{},
+		Publish: page.PublishPolicy{
+			Now: func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) },
+		},
+		Generators: []page.Generator{
+			{
+				Config: page.Config{
+					Renderer: MockRenderer{},
+					Pattern:  ":slug",
+					GetPaths: func() []string {
+						return []string{"live", "draft", "scheduled"}
+					},
+					GetData: func(p page.PagePayload) map[string]any {
+						return posts[p.Params["slug"]]
+					},
+				},
+			},
+		},
+	}
+
+	report, err := b.BuildWithReport()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.Written) != 1 || report.Written[0] != "live.html" {
+		t.Errorf("unexpected written pages: %v", report.Written)
+	}
+
+	want := []builder.SkippedPage{
+		{Path: "draft", Reason: "draft"},
+		{Path: "scheduled", Reason: "scheduled for 2030-01-01T00:00:00Z"},
+	}
+	if len(report.Skipped) != len(want) {
+		t.Fatalf("unexpected skipped pages: %v", report.Skipped)
+	}
+	for i, s := range want {
+		if report.Skipped[i] != s {
+			t.Errorf("unexpected skipped page at %d: got %v, want %v", i, report.Skipped[i], s)
+		}
+	}
+}
+
+type recordingWriter struct {
+	files map[string]string
+}
+
+func (w *recordingWriter) Write(path string, content string) error {
+	w.files[path] = content
+	return nil
+}
+
+func TestBuilder_Build_MultipleOutputFormats(t *testing.T) {
+	w := &recordingWriter{files: map[string]string{}}
+	jsonFormat := page.JSONFormat
+	jsonFormat.Renderer = MockRendererFail{}
+
+	b := builder.Builder{
+		OutputDir: "out",
+		Writer:    w,
+		Generators: []page.Generator{
+			{
+				Config: page.Config{
+					Renderer: MockRenderer{},
+					GetPaths: func() []string {
+						return []string{"posts"}
+					},
+					Outputs: []page.OutputFormat{page.HTMLFormat, page.TextFormat},
+				},
+			},
+		},
+	}
+
+	report, err := b.BuildWithReport()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, p := range []string{"out/posts.html", "out/posts.txt"} {
+		if w.files[filepath.FromSlash(p)] != "hello world" {
+			t.Errorf("expected %s to be written, got %v", p, w.files)
+		}
+	}
+	if len(report.Written) != 2 || report.Written[1] != "posts.txt" {
+		t.Errorf("unexpected report: %v", report.Written)
+	}
+
+	b.Generators[0].Config.Outputs = []page.OutputFormat{page.HTMLFormat, jsonFormat}
+	if err := b.Build(); err == nil {
+		t.Fatal("expected error from failing format renderer")
+	}
+}
+
+func TestBuilder_Build_DottedPagePath(t *testing.T) {
+	dir := t.TempDir()
+
+	b := builder.Builder{
+		OutputDir: dir,
+		Writer:    writer.NewFileWriter(),
+		Generators: []page.Generator{
+			{Config: page.Config{
+				Renderer: MockRenderer{},
+				GetPaths: func() []string { return []string{"blog/v1.2", "about.me"} },
+			}},
+		},
+	}
+
+	if err := b.Build(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, file := range []string{"blog/v1.2.html", "about.me.html"} {
+		if _, err := os.Stat(filepath.Join(dir, filepath.FromSlash(file))); err != nil {
+			t.Errorf("expected %s to be written: %v", file, err)
+		}
+	}
+}
+
+type exactRecordingWriter struct {
+	recordingWriter
+	exact map[string]string
+}
+
+func (w *exactRecordingWriter) WriteExact(path string, content string) error {
+	w.exact[path] = content
+	return nil
+}
+
+func TestBuilder_Build_Redirects(t *testing.T) {
+	w := &exactRecordingWriter{
+		recordingWriter: recordingWriter{files: map[string]string{}},
+		exact:           map[string]string{},
+	}
+
+	b := builder.Builder{
+		OutputDir: "out",
+		Writer:    w,
+		Redirects: map[string]string{"legacy": "https://example.com/"},
+		RedirectFiles: []redirect.File{
+			redirect.Netlify,
+		},
+		Generators: []page.Generator{
+			{
+				Config: page.Config{
+					Renderer: MockRenderer{},
+					GetPaths: func() []string {
+						return []string{"blog/new"}
+					},
+					GetData: func(p page.PagePayload) map[string]any {
+						return map[string]any{"aliases": []any{"/blog/old"}}
+					},
+				},
+			},
+		},
+	}
+
+	report, err := b.BuildWithReport()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	alias := w.exact[filepath.Join("out", "blog", "old.html")]
+	if !strings.Contains(alias, `url=/blog/new.html`) {
+		t.Errorf("alias page not written: %v", w.exact)
+	}
+	if !strings.Contains(w.exact[filepath.Join("out", "legacy.html")], "https://example.com/") {
+		t.Errorf("redirect page not written: %v", w.exact)
+	}
+
+	want := "/blog/old /blog/new.html 301\n/legacy https://example.com/ 301\n"
+	if got := w.exact[filepath.Join("out", "_redirects")]; got != want {
+		t.Errorf("unexpected _redirects:\n%s\nexpected:\n%s", got, want)
+	}
+
+	if len(report.Redirects) != 2 || report.Redirects[0].From != "/blog/old" {
+		t.Errorf("unexpected report redirects: %v", report.Redirects)
+	}
+}
+
+func TestBuilder_Build_RedirectConflictsWithPage(t *testing.T) {
+	b := builder.Builder{
+		OutputDir: "out",
+		Writer:    MockWriter{},
+		Redirects: map[string]string{"/a": "/b.html"},
+		Generators: []page.Generator{
+			{
+				Config: page.Config{
+					Renderer: MockRenderer{},
+					GetPaths: func() []string {
+						return []string{"a", "b"}
+					},
+				},
+			},
+		},
+	}
+
+	err := b.Build()
+	if err == nil || !strings.Contains(err.Error(), "conflicts with page") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
+
+func TestBuilder_Build_RedirectConflictsWithFile(t *testing.T) {
+	cases := []struct {
+		from    string
+		outputs []page.OutputFormat
+	}{
+		{from: "/a.html"},
+		{from: "/a.json", outputs: []page.OutputFormat{page.HTMLFormat, page.JSONFormat}},
+		{from: "/a/"},
+	}
+
+	for _, c := range cases {
+		b := builder.Builder{
+			OutputDir: "out",
+			Writer:    MockWriter{},
+			Redirects: map[string]string{c.from: "/b.html"},
+			Generators: []page.Generator{
+				{
+					Config: page.Config{
+						Renderer: MockRenderer{},
+						Outputs:  c.outputs,
+						GetPaths: func() []string {
+							return []string{"a"}
+						},
+					},
+				},
+			},
+		}
+
+		err := b.Build()
+		if err == nil || !strings.Contains(err.Error(), "conflicts with page") {
+			t.Errorf("%s: expected conflict error, got %v", c.from, err)
+		}
+	}
+}
+
+func TestBuilder_Build_RedirectFilesNeedExactWriter(t *testing.T) {
+	b := builder.Builder{
+		OutputDir:     "out",
+		Writer:        &recordingWriter{files: map[string]string{}},
+		Redirects:     map[string]string{"old": "/new.html"},
+		RedirectFiles: []redirect.File{redirect.Netlify},
+	}
+
+	err := b.Build()
+	if err == nil || !strings.Contains(err.Error(), "writer.ExactWriter") {
+		t.Fatalf("expected writer error, got %v", err)
+	}
+}
+
+func TestBuilder_Build_DuplicateRedirect(t *testing.T) {
+	b := builder.Builder{
+		OutputDir: "out",
+		Writer:    MockWriter{},
+		Redirects: map[string]string{"old": "/b.html"},
+		Generators: []page.Generator{
+			{
+				Config: page.Config{
+					Renderer: MockRenderer{},
+					GetPaths: func() []string {
+						return []string{"b"}
+					},
+					GetData: func(p page.PagePayload) map[string]any {
+						return map[string]any{"aliases": []string{"old"}}
+					},
+				},
+			},
+		},
+	}
+
+	err := b.Build()
+	if err == nil || !strings.Contains(err.Error(), "duplicate redirect") {
+		t.Fatalf("expected duplicate error, got %v", err)
+	}
+}
+
+func TestBuilder_Build_PrettyURLs(t *testing.T) {
+	w := &recordingWriter{files: map[string]string{}}
+	var payloadURL string
+
+	b := builder.Builder{
+		OutputDir: "out",
+		Writer:    w,
+		URLs:      urls.Config{Style: urls.Pretty, TrailingSlash: true},
+		Generators: []page.Generator{
+			{
+				Config: page.Config{
+					Renderer: MockRenderer{},
+					GetPaths: func() []string {
+						return []string{"index", "blog/post"}
+					},
+					GetData: func(p page.PagePayload) map[string]any {
+						payloadURL = p.URLs.URL(p.Path, "")
+						return map[string]any{"aliases": []string{"old/" + p.Path}}
+					},
+				},
+			},
+		},
+	}
+
+	report, err := b.BuildWithReport()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, p := range []string{"out/index.html", "out/blog/post/index.html", "out/old/blog/post/index.html"} {
+		if _, ok := w.files[filepath.FromSlash(p)]; !ok {
+			t.Errorf("expected %s to be written, got %v", p, report.Written)
+		}
+	}
+
+	if payloadURL != "/blog/post/" {
+		t.Errorf("unexpected payload url: %q", payloadURL)
+	}
+
+	if report.Redirects[0].To != "/blog/post/" {
+		t.Errorf("unexpected redirect target: %v", report.Redirects)
+	}
+}
+
+type contextRecorder struct {
+	ctx rendering.RenderContext
+}
+
+func (r *contextRecorder) Render(ctx rendering.RenderContext) (output string, err error) {
+	r.ctx = ctx
+	return `<a href="/blog/">Blog</a>`, nil
+}
+
+func TestBuilder_Build_BaseURL(t *testing.T) {
+	w := &recordingWriter{files: map[string]string{}}
+	r := &contextRecorder{}
+
+	b := builder.Builder{
+		OutputDir:   "out",
+		Writer:      w,
+		BaseURL:     "https://example.com/docs/",
+		RewriteURLs: true,
+		URLs:        urls.Config{Style: urls.Pretty, TrailingSlash: true},
+		Generators: []page.Generator{
+			{
+				Config: page.Config{
+					Renderer: r,
+					GetPaths: func() []string {
+						return []string{"about"}
+					},
+				},
+			},
+		},
+	}
+
+	if err := b.Build(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if r.ctx.Canonical != "https://example.com/docs/about/" {
+		t.Errorf("unexpected canonical: %q", r.ctx.Canonical)
+	}
+	if r.ctx.URL != "/docs/about/" {
+		t.Errorf("unexpected url: %q", r.ctx.URL)
+	}
+
+	got := w.files[filepath.Join("out", "about", "index.html")]
+	if got != `<a href="/docs/blog/">Blog</a>` {
+		t.Errorf("root-relative links were not rewritten: %q", got)
+	}
+}
+
+func TestBuilder_Build_Languages(t *testing.T) {
+	w := &recordingWriter{files: map[string]string{}}
+	r := &contextRecorder{}
+	bundle := i18n.NewBundle("en")
+	bundle.Add("de", map[string]any{"hello": "Hallo"})
+
+	var langs []string
+	b := builder.Builder{
+		OutputDir:    "out",
+		Writer:       w,
+		BaseURL:      "https://example.com/",
+		Translations: bundle,
+		Languages: []i18n.Language{
+			{Code: "en"},
+			{Code: "de", BaseURL: "https://example.de/"},
+		},
+		Generators: []page.Generator{
+			{
+				Config: page.Config{
+					Renderer: r,
+					GetPaths: func() []string {
+						return []string{"about"}
+					},
+					GetData: func(p page.PagePayload) map[string]any {
+						langs = append(langs, p.Lang)
+						return nil
+					},
+				},
+			},
+		},
+	}
+
+	report, err := b.BuildWithReport()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"en/about.html", "de/about.html", "index.html"}
+	if strings.Join(report.Written, ",") != filepath.FromSlash(strings.Join(want, ",")) {
+		t.Errorf("unexpected written files: %v", report.Written)
+	}
+	if strings.Join(langs, ",") != "en,de" {
+		t.Errorf("unexpected payload languages: %v", langs)
+	}
+
+	if r.ctx.Lang != "de" || r.ctx.Canonical != "https://example.de/about.html" {
+		t.Errorf("unexpected context for last page: %q %q", r.ctx.Lang, r.ctx.Canonical)
+	}
+	if r.ctx.Translate("hello") != "Hallo" {
+		t.Errorf("translator not bound to language")
+	}
+
+	alternates := r.ctx.Alternates
+	if len(alternates) != 2 || alternates[0].URL != "https://example.com/en/about.html" || alternates[1].URL != "https://example.de/about.html" {
+		t.Errorf("unexpected alternates: %v", alternates)
+	}
+
+	if !strings.Contains(w.files[filepath.Join("out", "index.html")], `hreflang="de" href="https://example.de/"`) {
+		t.Errorf("language redirect page missing: %v", w.files)
+	}
+}
+
+func TestBuilder_Build_GeneratorLanguages(t *testing.T) {
+	w := &recordingWriter{files: map[string]string{}}
+	r := &contextRecorder{}
+
+	b := builder.Builder{
+		OutputDir: "out",
+		Writer:    w,
+		BaseURL:   "https://example.com/",
+		Languages: []i18n.Language{{Code: "en"}, {Code: "de"}},
+		Generators: []page.Generator{
+			{
+				Config: page.Config{
+					Renderer:  MockRenderer{},
+					Languages: []string{""},
+					GetPaths:  func() []string { return []string{"index"} },
+				},
+			},
+			{
+				Config: page.Config{
+					Renderer:  r,
+					Languages: []string{"de"},
+					GetPaths:  func() []string { return []string{"impressum"} },
+				},
+			},
+		},
+	}
+
+	report, err := b.BuildWithReport()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"index.html", "de/impressum.html"}
+	if strings.Join(report.Written, ",") != filepath.FromSlash(strings.Join(want, ",")) {
+		t.Errorf("unexpected written files: %v", report.Written)
+	}
+	if got := w.files[filepath.Join("out", "index.html")]; got != "hello world" {
+		t.Errorf("root index was overwritten by the language redirect: %q", got)
+	}
+	if len(r.ctx.Alternates) != 1 || r.ctx.Alternates[0].Lang != "de" {
+		t.Errorf("unexpected alternates: %v", r.ctx.Alternates)
+	}
+}
+
+func TestBuilder_Build_ValidatesTemplates(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"layout.html":    `{{ define "base" }}{{ template "content" . }}{{ end }}`,
+		"broken.html":    `{{ define "content" }}{{ unknownFunc . }}{{ end }}`,
+		"nocontent.html": `{{ define "main" }}{{ end }}`,
+		"ok.html":        `{{ define "content" }}{{ .title }}{{ end }}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	renderer := rendering.HTMLRenderer{Layout: []string{filepath.Join(dir, "layout.html")}}
+	gen := func(tmpl string) page.Generator {
+		return page.Generator{Config: page.Config{
+			Template: filepath.Join(dir, tmpl),
+			Renderer: renderer,
+			GetPaths: func() []string {
+				t.Fatal("pages must not be generated when validation fails")
+				return nil
+			},
+		}}
+	}
+
+	b := builder.Builder{
+		OutputDir:  "/test",
+		Writer:     MockWriter{},
+		Generators: []page.Generator{gen("broken.html"), gen("nocontent.html"), gen("ok.html"), gen("missing.html")},
+	}
+
+	err := b.Build()
+	if err == nil {
+		t.Fatal("expected validation error")
+	}
+
+	msg := err.Error()
+	for _, want := range []string{
+		"template validation failed",
+		`layout.html do not define "root"`,
+		`function "unknownFunc" not defined`,
+		`nocontent.html does not define "content"`,
+		"missing.html",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("expected %q in error:\n%s", want, msg)
+		}
+	}
+	if strings.Count(msg, `do not define "root"`) != 1 {
+		t.Errorf("layout problem should be reported once:\n%s", msg)
+	}
+}
+
+func TestBuilder_Build_RenderErrorDetail(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := filepath.Join(dir, "post.html")
+	if err := os.WriteFile(tmpl, []byte("{{ define \"root\" }}\n{{ .Author.Name }}\n{{ end }}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := builder.Builder{
+		OutputDir: "/test",
+		Writer:    MockWriter{},
+		Generators: []page.Generator{{
+			Config: page.Config{
+				Template: tmpl,
+				Renderer: rendering.HTMLRenderer{},
+				GetPaths: func() []string { return []string{"blog/post"} },
+				GetData:  func(p page.PagePayload) map[string]any { return map[string]any{"Author": 1} },
+			},
+		}},
+	}
+
+	err := b.Build()
+	var renderErr *rendering.RenderError
+	if !errors.As(err, &renderErr) {
+		t.Fatalf("expected RenderError, got %v", err)
+	}
+	if renderErr.Page != "blog/post" || renderErr.Line != 2 {
+		t.Errorf("unexpected render error: %+v", renderErr)
+	}
+	if !strings.Contains(err.Error(), "hint: missing key .Name in data") || !strings.Contains(err.Error(), "> 2 | {{ .Author.Name }}") {
+		t.Errorf("expected detail in error:\n%v", err)
+	}
+}
+
+type streamWriter struct {
+	recordingWriter
+	streamed map[string]*strings.Builder
+}
+
+func (w *streamWriter) Create(path string) (io.WriteCloser, error) {
+	b := &strings.Builder{}
+	w.streamed[path] = b
+	return nopCloser{b}, nil
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+type streamRenderer struct{}
+
+func (r streamRenderer) Render(ctx rendering.RenderContext) (string, error) {
+	return `<a href="/x">buffered</a>`, nil
+}
+
+func (r streamRenderer) RenderTo(ctx rendering.RenderContext, w io.Writer) error {
+	_, err := io.WriteString(w, "streamed")
+	return err
+}
+
+func TestBuilder_Build_Streams(t *testing.T) {
+	newBuilder := func(w *streamWriter) builder.Builder {
+		return builder.Builder{
+			OutputDir: "out",
+			Writer:    w,
+			Generators: []page.Generator{{
+				Config: page.Config{
+					Renderer: streamRenderer{},
+					GetPaths: func() []string { return []string{"a"} },
+				},
+			}},
+		}
+	}
+
+	w := &streamWriter{recordingWriter{files: map[string]string{}}, map[string]*strings.Builder{}}
+	if err := newBuilder(w).Build(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := w.streamed[filepath.Join("out", "a.html")]; got == nil || got.String() != "streamed" {
+		t.Errorf("expected streamed page, got %v", w.streamed)
+	}
+	if len(w.files) != 0 {
+		t.Errorf("Write should not be used when streaming: %v", w.files)
+	}
+
+	// Rewriting URLs needs the whole page, so the builder falls back to Write.
+	w = &streamWriter{recordingWriter{files: map[string]string{}}, map[string]*strings.Builder{}}
+	b := newBuilder(w)
+	b.RewriteURLs = true
+	b.BaseURL = "https://example.com/docs/"
+	if err := b.Build(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := w.files[filepath.Join("out", "a.html")]; got != `<a href="/docs/x">buffered</a>` {
+		t.Errorf("expected buffered page, got %v", w.files)
+	}
+}
+
+type failingStreamRenderer struct{}
+
+func (r failingStreamRenderer) Render(ctx rendering.RenderContext) (string, error) {
+	return "", errors.New("not used")
+}
+
+func (r failingStreamRenderer) RenderTo(ctx rendering.RenderContext, w io.Writer) error {
+	io.WriteString(w, "<html><body>half")
+	return errors.New("render failed")
+}
+
+func TestBuilder_Build_StreamFailureKeepsPreviousFile(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.html")
+	if err := os.WriteFile(file, []byte("last good build"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	b := builder.Builder{
+		OutputDir: dir,
+		Writer:    writer.NewFileWriter(),
+		Generators: []page.Generator{{
+			Config: page.Config{
+				Renderer: failingStreamRenderer{},
+				GetPaths: func() []string { return []string{"a"} },
+			},
+		}},
+	}
+
+	if err := b.Build(); err == nil {
+		t.Fatal("expected render error")
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil || string(data) != "last good build" {
+		t.Errorf("previous page was overwritten: %q, err %v", data, err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("temporary file was left behind: %v", entries)
+	}
+}
+
+func TestBuilder_Build_Transforms(t *testing.T) {
+	w := &streamWriter{recordingWriter{files: map[string]string{}}, map[string]*strings.Builder{}}
+	b := builder.Builder{
+		OutputDir: "out",
+		Writer:    w,
+		Generators: []page.Generator{{
+			Config: page.Config{
+				Renderer: streamRenderer{},
+				GetPaths: func() []string { return []string{"a"} },
+			},
+		}},
+		Transforms: []builder.Transform{
+			func(file, content string) (string, error) { return strings.ToUpper(content), nil },
+			func(file, content string) (string, error) { return file + ":" + content, nil },
+		},
+	}
+
+	if err := b.Build(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := w.files[filepath.Join("out", "a.html")]; got != `a.html:<A HREF="/X">BUFFERED</A>` {
+		t.Errorf("expected transformed page, got %v", w.files)
+	}
+
+	b.Transforms = []builder.Transform{func(file, content string) (string, error) {
+		return "", errors.New("boom")
+	}}
+	if err := b.Build(); err == nil || !strings.Contains(err.Error(), "failed to transform page") {
+		t.Errorf("expected transform error, got %v", err)
+	}
+}
+
+func TestBuilder_BuildWithReport_Debug(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html": {Data: []byte(`{{ define "root" }}{{ .title }}{{ .missing }}{{ end }}`)},
+	}
+	w := &recordingWriter{files: map[string]string{}}
+	b := builder.Builder{
+		OutputDir: "out",
+		Writer:    w,
+		Generators: []page.Generator{{
+			Config: page.Config{
+				Template: "page.html",
+				Renderer: rendering.HTMLRenderer{FS: fsys, Debug: true},
+				GetPaths: func() []string { return []string{"a"} },
+				GetData:  func(payload page.PagePayload) map[string]any { return map[string]any{"title": "A"} },
+			},
+		}},
+	}
+
+	report, err := b.BuildWithReport()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := w.files[filepath.Join("out", "a.html")]; got != "A" {
+		t.Errorf("unexpected output: %q", got)
+	}
+	if got := report.Templates["a.html"]; len(got) != 1 || got[0] != "root" {
+		t.Errorf("unexpected templates: %v", report.Templates)
+	}
+	if len(report.Warnings) != 1 || report.Warnings[0].Path != "a.html" || !strings.Contains(report.Warnings[0].Message, `page.html:1:`) {
+		t.Errorf("unexpected warnings: %+v", report.Warnings)
+	}
+}
+
+func TestBuilder_BuildWithReport_TOCs(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html":  {Data: []byte(`{{ define "root" }}<h1>Site</h1>{{ template "content" . }}{{ end }}{{ define "content" }}<h2>{{ .title }}</h2><h3>Details</h3>{{ end }}`)},
+		"plain.html": {Data: []byte(`{{ define "root" }}<h2>Plain</h2>{{ end }}`)},
+	}
+	w := &recordingWriter{files: map[string]string{}}
+	b := builder.Builder{
+		OutputDir: "out",
+		Writer:    w,
+		Generators: []page.Generator{
+			{
+				Config: page.Config{
+					Template: "page.html",
+					Renderer: rendering.HTMLRenderer{FS: fsys, Headings: &toc.Options{}},
+					GetPaths: func() []string { return []string{"a", "b"} },
+					GetData: func(payload page.PagePayload) map[string]any {
+						return map[string]any{"title": strings.ToUpper(payload.Path)}
+					},
+				},
+			},
+			{
+				Config: page.Config{
+					Template: "plain.html",
+					Renderer: rendering.HTMLRenderer{FS: fsys},
+					GetPaths: func() []string { return []string{"c"} },
+				},
+			},
+		},
+	}
+
+	report, err := b.BuildWithReport()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(report.TOCs) != 2 {
+		t.Fatalf("expected TOCs for a.html and b.html, got %v", report.TOCs)
+	}
+	for file, title := range map[string]string{"a.html": "A", "b.html": "B"} {
+		contents := report.TOCs[file]
+		if len(contents) != 1 || contents[0].ID != strings.ToLower(title) || contents[0].Text != title || len(contents[0].Children) != 1 {
+			t.Errorf("%s: unexpected TOC %+v", file, contents)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package builder
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/janmarkuslanger/ssgo/i18n"
+	"github.com/janmarkuslanger/ssgo/redirect"
+	"github.com/janmarkuslanger/ssgo/urls"
+	"github.com/janmarkuslanger/ssgo/writer"
+)
+
+func (b Builder) writeRedirects(redirects []redirect.Redirect, written []string) error {
+	redirect.Sort(redirects)
+
+	var exact writer.ExactWriter
+	if len(b.RedirectFiles) > 0 {
+		w, ok := b.Writer.(writer.ExactWriter)
+		if !ok {
+			return fmt.Errorf("redirect files need a writer that implements writer.ExactWriter, got %T", b.Writer)
+		}
+		exact = w
+	}
+
+	pages := make(map[string]struct{}, len(written))
+	for _, file := range written {
+		pages[file] = struct{}{}
+	}
+
+	seen := make(map[string]struct{}, len(redirects))
+	for _, r := range redirects {
+		file, err := b.redirectFile(r.From)
+		if err != nil {
+			return fmt.Errorf("invalid redirect %s: %w", r.From, err)
+		}
+		if _, ok := pages[file]; ok {
+			return fmt.Errorf("redirect from %s conflicts with page %s", r.From, filepath.ToSlash(file))
+		}
+		if _, ok := seen[file]; ok {
+			return fmt.Errorf("duplicate redirect from %s", r.From)
+		}
+		seen[file] = struct{}{}
+
+		if err := b.writeFile(filepath.Join(b.OutputDir, file), redirect.Page(r.To)); err != nil {
+			return fmt.Errorf("failed to write redirect %s: %w", r.From, err)
+		}
+	}
+
+	for _, f := range b.RedirectFiles {
+		if err := exact.WriteExact(filepath.Join(b.OutputDir, f.Path), f.Render(redirects)); err != nil {
+			return fmt.Errorf("failed to write redirect file %s: %w", f.Path, err)
+		}
+	}
+
+	return nil
+}
+
+// redirectFile returns the file a redirect from the URL path from is written
+// to, relative to OutputDir. Paths with an extension such as /old.html are
+// written as they are, others follow the URL style.
+func (b Builder) redirectFile(from string) (string, error) {
+	cleanPath, err := cleanPagePath(strings.TrimPrefix(from, "/"))
+	if err != nil {
+		return "", err
+	}
+	if !strings.HasSuffix(from, "/") && path.Ext(from) != "" {
+		return cleanPath, nil
+	}
+	return b.URLs.File(cleanPath, ".html"), nil
+}
+
+func (b Builder) writeLanguageRedirect(report *Report) error {
+	if len(b.Languages) == 0 {
+		return nil
+	}
+
+	file := b.URLs.File("index", ".html")
+	if slices.Contains(report.Written, file) {
+		return nil
+	}
+
+	if err := b.writeFile(filepath.Join(b.OutputDir, file), b.LanguageRedirect()); err != nil {
+		return fmt.Errorf("failed to write language redirect: %w", err)
+	}
+	report.Written = append(report.Written, file)
+
+	return nil
+}
+
+// LanguageRedirect returns the page served at the site root of multilingual
+// sites, which sends visitors to the home page of their language.
+func (b Builder) LanguageRedirect() string {
+	var alternates []i18n.Alternate
+	for _, l := range b.SiteLanguages() {
+		alternates = append(alternates, i18n.Alternate{Lang: l.Code, URL: urls.AbsURL(l.BaseURL, "/")})
+	}
+	return i18n.RedirectPage(alternates)
+}
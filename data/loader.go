@@ -0,0 +1,115 @@
+package data
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+func NewLoader(dir string) *Loader {
+	return &Loader{
+		Dir: dir,
+	}
+}
+
+// Loader reads every supported file below Dir into a nested map keyed by
+// the file path without extension, e.g. "authors/jan.yaml" becomes
+// data["authors"]["jan"]. The result is cached until Reset is called.
+type Loader struct {
+	Dir string
+
+	mu    sync.Mutex
+	cache map[string]any
+}
+
+func (l *Loader) Load() (map[string]any, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cache != nil {
+		return l.cache, nil
+	}
+
+	site, err := LoadDir(l.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	l.cache = site
+	return site, nil
+}
+
+func (l *Loader) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.cache = nil
+}
+
+func LoadDir(dir string) (map[string]any, error) {
+	site := make(map[string]any)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("walk error: %w", err)
+		}
+		if d.IsDir() || !IsSupported(path) {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return fmt.Errorf("failed to get relative path from %s to %s: %w", dir, path, err)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read data file %s: %w", path, err)
+		}
+
+		value, err := Parse(path, content)
+		if err != nil {
+			return err
+		}
+
+		return insert(site, relPath, value)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return site, nil
+}
+
+func insert(site map[string]any, relPath string, value any) error {
+	key := strings.TrimSuffix(filepath.ToSlash(relPath), filepath.Ext(relPath))
+	segments := strings.Split(key, "/")
+
+	current := site
+	for _, s := range segments[:len(segments)-1] {
+		existing, ok := current[s]
+		if !ok {
+			next := make(map[string]any)
+			current[s] = next
+			current = next
+			continue
+		}
+
+		next, ok := existing.(map[string]any)
+		if !ok {
+			return fmt.Errorf("data key conflict at %q for file %s", s, relPath)
+		}
+		current = next
+	}
+
+	last := segments[len(segments)-1]
+	if _, ok := current[last]; ok {
+		return fmt.Errorf("data key conflict at %q for file %s", last, relPath)
+	}
+	current[last] = value
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package data_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/janmarkuslanger/ssgo/data"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	fullPath := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+		t.Fatalf("mkdir error: %v", err)
+	}
+	if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+}
+
+func TestLoadDir_AllFormats(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "site.json", `{"title": "My Site"}`)
+	writeFile(t, dir, "authors/jan.yaml", "name: Jan\nlinks:\n  - github\n")
+	writeFile(t, dir, "config.toml", "lang = \"en\"\n[social]\nmastodon = \"@jan\"\n")
+	writeFile(t, dir, "products.csv", "id,name\n1,Shoe\n2,Hat\n")
+	writeFile(t, dir, "notes.txt", "ignored")
+
+	site, err := data.LoadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := site["site"].(map[string]any)["title"]; got != "My Site" {
+		t.Errorf("unexpected json value: %v", got)
+	}
+
+	authors := site["authors"].(map[string]any)
+	if got := authors["jan"].(map[string]any)["name"]; got != "Jan" {
+		t.Errorf("unexpected yaml value: %v", got)
+	}
+
+	social := site["config"].(map[string]any)["social"].(map[string]any)
+	if got := social["mastodon"]; got != "@jan" {
+		t.Errorf("unexpected toml value: %v", got)
+	}
+
+	products := site["products"].([]map[string]string)
+	if len(products) != 2 || products[1]["name"] != "Hat" {
+		t.Errorf("unexpected csv value: %v", products)
+	}
+
+	if _, ok := site["notes"]; ok {
+		t.Error("unsupported files should be ignored")
+	}
+}
+
+func TestLoadDir_ParseErrorHasFileAndLine(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		line    int
+	}{
+		{name: "broken.json", content: "{\n  \"a\": 1,\n  \"b\": \n}", line: 4},
+		{name: "broken.yaml", content: "a: 1\nb: c: d\n", line: 2},
+		{name: "broken.toml", content: "a = 1\nb = \n", line: 2},
+		{name: "broken.csv", content: "a,b\n1,2\n3\n", line: 3},
+	}
+
+	for _, c := range cases {
+		dir := t.TempDir()
+		writeFile(t, dir, c.name, c.content)
+
+		_, err := data.LoadDir(dir)
+		if err == nil {
+			t.Fatalf("%s: expected an error", c.name)
+		}
+
+		var parseErr *data.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("%s: expected ParseError, got %T", c.name, err)
+		}
+		if !strings.HasSuffix(parseErr.File, c.name) {
+			t.Errorf("%s: unexpected file %q", c.name, parseErr.File)
+		}
+		if parseErr.Line != c.line {
+			t.Errorf("%s: unexpected line: got %d, want %d", c.name, parseErr.Line, c.line)
+		}
+	}
+}
+
+func TestLoadDir_KeyConflict(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "authors.json", `{}`)
+	writeFile(t, dir, "authors.yaml", "a: 1")
+
+	_, err := data.LoadDir(dir)
+	if err == nil || !strings.Contains(err.Error(), "data key conflict") {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
+
+func TestLoadDir_MissingDir(t *testing.T) {
+	_, err := data.LoadDir(filepath.Join(t.TempDir(), "missing"))
+	if err == nil {
+		t.Fatal("expected an error for missing dir")
+	}
+}
+
+func TestLoader_CachesUntilReset(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "site.json", `{"title": "First"}`)
+
+	l := data.NewLoader(dir)
+	first, err := l.Load()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	writeFile(t, dir, "site.json", `{"title": "Second"}`)
+
+	cached, _ := l.Load()
+	if got := cached["site"].(map[string]any)["title"]; got != "First" {
+		t.Errorf("expected cached value, got %v", got)
+	}
+	if got := first["site"].(map[string]any)["title"]; got != "First" {
+		t.Errorf("unexpected value: %v", got)
+	}
+
+	l.Reset()
+	reloaded, _ := l.Load()
+	if got := reloaded["site"].(map[string]any)["title"]; got != "Second" {
+		t.Errorf("expected reloaded value, got %v", got)
+	}
+}
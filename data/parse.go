@@ -0,0 +1,166 @@
+package data
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+type ParseError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+	}
+	return fmt.Sprintf("%s: %v", e.File, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func IsSupported(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", ".yaml", ".yml", ".toml", ".csv":
+		return true
+	}
+	return false
+}
+
+func Parse(path string, content []byte) (any, error) {
+	var (
+		value any
+		err   error
+		line  int
+	)
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		value, line, err = parseJSON(content)
+	case ".yaml", ".yml":
+		value, line, err = parseYAML(content)
+	case ".toml":
+		value, line, err = parseTOML(content)
+	case ".csv":
+		value, line, err = parseCSV(content)
+	default:
+		return nil, &ParseError{File: path, Err: errors.New("unsupported data file format")}
+	}
+
+	if err != nil {
+		return nil, &ParseError{File: path, Line: line, Err: err}
+	}
+
+	return value, nil
+}
+
+func parseJSON(content []byte) (any, int, error) {
+	var v any
+	err := json.Unmarshal(content, &v)
+	if err == nil {
+		return v, 0, nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		return nil, lineAt(content, syntaxErr.Offset), err
+	}
+	return nil, 0, err
+}
+
+var yamlLinePattern = regexp.MustCompile(`line (\d+)`)
+
+func parseYAML(content []byte) (any, int, error) {
+	var v any
+	if err := yaml.Unmarshal(content, &v); err != nil {
+		line := 0
+		if m := yamlLinePattern.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		return nil, line, err
+	}
+	return normalize(v), 0, nil
+}
+
+func parseTOML(content []byte) (any, int, error) {
+	var v map[string]any
+	if err := toml.Unmarshal(content, &v); err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, parseErr.Position.Line, errors.New(parseErr.Message)
+		}
+		return nil, 0, err
+	}
+	return v, 0, nil
+}
+
+func parseCSV(content []byte) (any, int, error) {
+	records, err := csv.NewReader(bytes.NewReader(content)).ReadAll()
+	if err != nil {
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, parseErr.Line, parseErr.Err
+		}
+		return nil, 0, err
+	}
+
+	rows := []map[string]string{}
+	if len(records) == 0 {
+		return rows, 0, nil
+	}
+
+	header := records[0]
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, key := range header {
+			row[key] = record[i]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, 0, nil
+}
+
+// normalize converts map[any]any values produced by YAML into
+// map[string]any so templates can access them by key.
+func normalize(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, val := range t {
+			t[k] = normalize(val)
+		}
+		return t
+	case map[any]any:
+		m := make(map[string]any, len(t))
+		for k, val := range t {
+			m[fmt.Sprint(k)] = normalize(val)
+		}
+		return m
+	case []any:
+		for i, val := range t {
+			t[i] = normalize(val)
+		}
+		return t
+	}
+	return v
+}
+
+func lineAt(content []byte, offset int64) int {
+	if offset > int64(len(content)) {
+		offset = int64(len(content))
+	}
+	return bytes.Count(content[:offset], []byte("\n")) + 1
+}
@@ -0,0 +1,47 @@
+package dev
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/janmarkuslanger/ssgo/rendering"
+)
+
+var errorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Render error</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2rem; color: #222; }
+h1 { color: #b00020; font-size: 1.4rem; }
+.hint { background: #fff8e1; padding: .5rem 1rem; border-left: 4px solid #ffb300; }
+pre { background: #f5f5f5; padding: 1rem; overflow-x: auto; }
+.line { display: block; }
+.current { background: #ffe0e0; font-weight: bold; }
+.num { color: #888; user-select: none; }
+</style>
+</head>
+<body>
+<h1>Failed to render {{ with .Page }}page {{ . }}{{ else }}page{{ end }}</h1>
+{{ with .Template }}<p><code>{{ . }}{{ with $.Line }}:{{ . }}{{ end }}{{ with $.Column }}:{{ . }}{{ end }}</code></p>{{ end }}
+<p>{{ .Message }}</p>
+{{ with .Hint }}<p class="hint">{{ . }}</p>{{ end }}
+{{ with .Excerpt }}<pre>{{ range . }}<span class="line{{ if eq .Number $.Line }} current{{ end }}"><span class="num">{{ printf "%4d" .Number }} | </span>{{ .Text }}</span>{{ end }}</pre>{{ end }}
+</body>
+</html>
+`))
+
+// writeRenderError responds with an error page describing err. Errors that
+// are not a rendering.RenderError are shown with their message only.
+func writeRenderError(w http.ResponseWriter, path string, err error) {
+	var renderErr *rendering.RenderError
+	if !errors.As(err, &renderErr) {
+		renderErr = &rendering.RenderError{Page: path, Message: err.Error(), Err: err}
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusInternalServerError)
+	errorPage.Execute(w, renderErr)
+}
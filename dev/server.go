@@ -2,49 +2,139 @@ package dev
 
 import (
 	"net/http"
+	"path/filepath"
+	"strings"
 
 	"github.com/janmarkuslanger/ssgo/builder"
+	"github.com/janmarkuslanger/ssgo/urls"
 )
 
+type Options struct {
+	// IncludeUnpublished serves drafts, scheduled and expired pages.
+	IncludeUnpublished bool
+}
+
 func NewServer(builder builder.Builder) http.Handler {
+	return NewServerWithOptions(builder, Options{})
+}
+
+func NewServerWithOptions(builder builder.Builder, opts Options) http.Handler {
 	mux := http.NewServeMux()
 
+	publish := builder.Publish
+	if opts.IncludeUnpublished {
+		publish.IncludeUnpublished = true
+	}
+
 	pagePaths := make(map[string]struct{})
 	for _, g := range builder.Generators {
 		if g.Config.GetPaths == nil {
 			continue
 		}
-		for _, path := range g.Config.GetPaths() {
-			pagePaths[path] = struct{}{}
-			mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
-				if err := builder.RunTasks(builder.BeforeTasks); err != nil {
-					panic(err)
-				}
 
-				p := g.GeneratePageInstance(path)
-				c, err := p.Render()
+		for _, lang := range builder.GeneratorLanguages(g) {
+			prefix := ""
+			if lang.Code != "" {
+				prefix = "/" + lang.Code
+			}
 
-				if err != nil {
-					panic(err)
-				}
+			for _, path := range g.Config.GetPaths() {
+				for i, o := range g.Formats() {
+					canonical := prefix + builder.URLs.URL(path, o.Extension)
+					for _, alias := range builder.URLs.Aliases(path, o.Extension) {
+						alias = prefix + alias
+						if _, ok := pagePaths[alias]; ok {
+							continue
+						}
+						pagePaths[alias] = struct{}{}
+						mux.Handle(alias, http.RedirectHandler(urls.RelURL(builder.BaseURL, canonical), http.StatusMovedPermanently))
+					}
 
-				if err := builder.RunTasks(builder.AfterTasks); err != nil {
-					panic(err)
-				}
+					// The extensionless page path is served as well, e.g.
+					// /blog/post for blog/post.html.
+					routes := []string{canonical}
+					if i == 0 && prefix == "" {
+						routes = append(routes, "/"+strings.TrimPrefix(path, "/"))
+					}
+
+					for _, route := range routes {
+						if _, ok := pagePaths[route]; ok {
+							continue
+						}
+						pagePaths[route] = struct{}{}
+						mux.HandleFunc(route, func(w http.ResponseWriter, r *http.Request) {
+							if err := builder.RunTasks(builder.BeforeTasks); err != nil {
+								panic(err)
+							}
+
+							site, err := builder.LoadData()
+							if err != nil {
+								panic(err)
+							}
+
+							gen := builder.PrepareGenerator(g, site, lang)
+							gen.Publish = publish
+							p := gen.GeneratePageInstance(path)
+							if !publish.IsPublished(p.Data) {
+								http.NotFound(w, r)
+								return
+							}
 
-				w.Write([]byte(c))
-			})
+							c, err := p.RenderOutput(o)
+
+							if err != nil {
+								writeRenderError(w, path, err)
+								return
+							}
+
+							if builder.RewriteURLs && (o.Extension == "" || o.Extension == ".html") {
+								c = urls.RewriteRootRelative(c, builder.BaseURL)
+							}
+
+							c, err = builder.ApplyTransforms(builder.URLs.File(filepath.Join(lang.Code, strings.TrimPrefix(path, "/")), o.Extension), c)
+							if err != nil {
+								writeRenderError(w, path, err)
+								return
+							}
+
+							if err := builder.RunTasks(builder.AfterTasks); err != nil {
+								panic(err)
+							}
+
+							if o.MediaType != "" {
+								w.Header().Set("Content-Type", o.MediaType)
+							}
+							w.Write([]byte(c))
+						})
+					}
+				}
+			}
 		}
 	}
 
+	if _, ok := pagePaths["/"]; !ok && len(builder.Languages) > 0 {
+		pagePaths["/"] = struct{}{}
+		page := builder.LanguageRedirect()
+		mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(page))
+		})
+	}
+
 	fs := http.FileServer(http.Dir(builder.OutputDir))
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if _, ok := pagePaths[r.URL.Path]; ok {
 			mux.ServeHTTP(w, r)
 			return
 		}
 		fs.ServeHTTP(w, r)
 	})
+
+	basePath := urls.BasePath(builder.BaseURL)
+	if basePath == "/" {
+		return handler
+	}
+
+	return http.StripPrefix(strings.TrimSuffix(basePath, "/"), handler)
 }
 
 func StartServer(builder builder.Builder) {
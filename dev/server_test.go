@@ -12,9 +12,11 @@ import (
 	"time"
 
 	"github.com/janmarkuslanger/ssgo/builder"
+	"github.com/janmarkuslanger/ssgo/i18n"
 	"github.com/janmarkuslanger/ssgo/page"
 	"github.com/janmarkuslanger/ssgo/rendering"
 	"github.com/janmarkuslanger/ssgo/task"
+	"github.com/janmarkuslanger/ssgo/urls"
 	"github.com/janmarkuslanger/ssgo/writer"
 
 	"github.com/janmarkuslanger/ssgo/dev"
@@ -219,18 +221,115 @@ func TestNewServer_RendersRegisteredPaths(t *testing.T) {
 
 }
 
-func TestNewServer_PanicsOnRenderError(t *testing.T) {
+func TestNewServer_RelativePaths(t *testing.T) {
+	_, tpl := makeTempTemplates(t)
+	layout, _ := makeTempTemplates(t)
+
+	b := builder.Builder{
+		OutputDir: t.TempDir(),
+		Writer:    &writer.FileWriter{},
+		Generators: []page.Generator{{
+			Config: page.Config{
+				Template: tpl,
+				Renderer: rendering.HTMLRenderer{Layout: []string{layout}},
+				GetPaths: func() []string { return []string{"index", "blog/post"} },
+				GetData:  func(p page.PagePayload) map[string]any { return map[string]any{"Content": p.Path} },
+			},
+		}},
+	}
+	if err := b.Build(); err != nil {
+		t.Fatalf("the builder must accept the paths: %v", err)
+	}
+
+	mux := dev.NewServer(b)
+	for path, want := range map[string]string{
+		"/":               "index",
+		"/blog/post.html": "blog/post",
+		"/blog/post":      "blog/post",
+	} {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		if rec.Code != http.StatusOK || rec.Body.String() != want {
+			t.Errorf("%s: got %d %q, want %q", path, rec.Code, rec.Body.String(), want)
+		}
+	}
+}
+
+func TestNewServer_RenderErrorPage(t *testing.T) {
 	b := makeBrokenBuilder(t)
 	mux := dev.NewServer(b)
 
-	defer func() {
-		if r := recover(); r == nil {
-			t.Fatalf("expected panic on render error")
-		}
-	}()
 	req := httptest.NewRequest(http.MethodGet, "/broken", nil)
 	rec := httptest.NewRecorder()
 	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected 500 on render error, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "Failed to render page /broken") || !strings.Contains(body, "does-not-exist.html") {
+		t.Errorf("unexpected error page: %s", body)
+	}
+}
+
+func TestNewServer_Transforms(t *testing.T) {
+	b := makeTestBuilder(t)
+	var files []string
+	b.Transforms = []builder.Transform{func(file, content string) (string, error) {
+		files = append(files, file)
+		if file == "about.html" {
+			return "", fmt.Errorf("transform failed")
+		}
+		return content, nil
+	}}
+	mux := dev.NewServer(b)
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/about", nil))
+	if rec.Code != http.StatusInternalServerError || !strings.Contains(rec.Body.String(), "transform failed") {
+		t.Errorf("expected error page, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	if strings.Join(files, ",") != "index.html,about.html" {
+		t.Errorf("transforms got unexpected files: %v", files)
+	}
+}
+
+func TestNewServer_RenderErrorPageShowsSource(t *testing.T) {
+	layout, _ := makeTempTemplates(t)
+	tpl := filepath.Join(t.TempDir(), "post.html")
+	if err := os.WriteFile(tpl, []byte("{{define \"content\"}}\n<h1>{{ .Title.Name }}</h1>\n{{end}}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	b := builder.Builder{
+		OutputDir: t.TempDir(),
+		Writer:    &writer.FileWriter{},
+		Generators: []page.Generator{{
+			Config: page.Config{
+				Template: tpl,
+				GetPaths: func() []string { return []string{"/post"} },
+				GetData:  func(p page.PagePayload) map[string]any { return map[string]any{"Title": "x"} },
+				Renderer: rendering.HTMLRenderer{Layout: []string{layout}},
+			},
+		}},
+	}
+
+	rec := httptest.NewRecorder()
+	dev.NewServer(b).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/post", nil))
+
+	body := rec.Body.String()
+	for _, want := range []string{"post.html:2:", "missing key .Name in data", `class="line current"`, "&lt;h1&gt;{{ .Title.Name }}&lt;/h1&gt;"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("expected %q in error page:\n%s", want, body)
+		}
+	}
 }
 
 func TestNewServer_PanicsOnBeforeTaskError(t *testing.T) {
@@ -289,3 +388,156 @@ func TestStartServer_PanicsOnListenError(t *testing.T) {
 		t.Fatalf("timeout waiting for StartServer panic")
 	}
 }
+
+func makeDraftBuilder(t *testing.T) builder.Builder {
+	t.Helper()
+	layout, tpl := makeTempTemplates(t)
+	renderer := rendering.HTMLRenderer{Layout: []string{layout}}
+
+	return builder.Builder{
+		OutputDir: t.TempDir(),
+		Writer:    &writer.FileWriter{},
+		Generators: []page.Generator{
+			{
+				Config: page.Config{
+					Pattern:  "/draft",
+					Template: tpl,
+					GetPaths: func() []string { return []string{"/draft"} },
+					GetData: func(p page.PagePayload) map[string]any {
+						return map[string]any{"Content": "draft", "draft": true}
+					},
+					Renderer: renderer,
+				},
+			},
+		},
+	}
+}
+
+func TestNewServer_HidesUnpublishedPages(t *testing.T) {
+	mux := dev.NewServer(makeDraftBuilder(t))
+
+	req := httptest.NewRequest(http.MethodGet, "/draft", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for draft, got %d", rec.Code)
+	}
+}
+
+func TestNewServerWithOptions_IncludesUnpublishedPages(t *testing.T) {
+	mux := dev.NewServerWithOptions(makeDraftBuilder(t), dev.Options{IncludeUnpublished: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/draft", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200 for draft, got %d", rec.Code)
+	}
+	if rec.Body.String() != "draft" {
+		t.Fatalf("unexpected body: %q", rec.Body.String())
+	}
+}
+
+func TestNewServer_ServesOutputFormats(t *testing.T) {
+	b := makeTestBuilder(t)
+	b.Generators[1].Config.Outputs = []page.OutputFormat{page.HTMLFormat, page.JSONFormat}
+	mux := dev.NewServer(b)
+
+	cases := []struct {
+		path        string
+		contentType string
+	}{
+		{path: "/about", contentType: "text/html"},
+		{path: "/about.html", contentType: "text/html"},
+		{path: "/about.json", contentType: "application/json"},
+	}
+
+	for _, c := range cases {
+		req := httptest.NewRequest(http.MethodGet, c.path, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: expected 200, got %d", c.path, rec.Code)
+		}
+		if ct := rec.Header().Get("Content-Type"); !strings.Contains(ct, c.contentType) {
+			t.Fatalf("GET %s: expected Content-Type %q, got %q", c.path, c.contentType, ct)
+		}
+	}
+}
+
+func TestNewServer_PrettyURLs(t *testing.T) {
+	b := makeTestBuilder(t)
+	b.URLs = urls.Config{Style: urls.Pretty, TrailingSlash: true}
+	mux := dev.NewServer(b)
+
+	req := httptest.NewRequest(http.MethodGet, "/about/", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "about" {
+		t.Fatalf("GET /about/: expected 200 with body, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/about", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMovedPermanently {
+		t.Fatalf("GET /about: expected 301, got %d", rec.Code)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/about/" {
+		t.Fatalf("GET /about: unexpected location %q", loc)
+	}
+}
+
+func TestNewServer_BaseURLPrefix(t *testing.T) {
+	b := makeTestBuilder(t)
+	b.BaseURL = "https://example.com/docs/"
+	mux := dev.NewServer(b)
+
+	req := httptest.NewRequest(http.MethodGet, "/docs/about", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK || rec.Body.String() != "about" {
+		t.Fatalf("GET /docs/about: expected 200 with body, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/docs/app.css", nil)
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /docs/app.css: expected 200, got %d", rec.Code)
+	}
+}
+
+func TestNewServer_Languages(t *testing.T) {
+	b := makeTestBuilder(t)
+	b.Generators = b.Generators[1:]
+	b.Languages = []i18n.Language{{Code: "en"}, {Code: "de"}}
+	mux := dev.NewServer(b)
+
+	for _, path := range []string{"/en/about.html", "/de/about.html"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK || rec.Body.String() != "about" {
+			t.Fatalf("GET %s: expected 200 with body, got %d %q", path, rec.Code, rec.Body.String())
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	if !strings.Contains(rec.Body.String(), `hreflang="de" href="/de/"`) {
+		t.Fatalf("GET /: expected language redirect page, got %q", rec.Body.String())
+	}
+
+	// The root redirect is the page the builder writes to index.html, also
+	// for languages with their own domain.
+	b.Languages[1].BaseURL = "https://example.de/"
+	rec = httptest.NewRecorder()
+	dev.NewServer(b).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := rec.Body.String(); body != b.LanguageRedirect() || !strings.Contains(body, `hreflang="de" href="https://example.de/"`) {
+		t.Errorf("GET /: unexpected language redirect page %q", body)
+	}
+}
@@ -1,3 +1,9 @@
 module github.com/janmarkuslanger/ssgo
 
 go 1.24.0
+
+require (
+	github.com/BurntSushi/toml v1.6.0
+	github.com/yuin/goldmark v1.8.2
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,104 @@
+package highlight
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Token classes used in the emitted spans, e.g. <span class="hl-keyword">.
+const (
+	Keyword  = "keyword"
+	Type     = "type"
+	Function = "function"
+	String   = "string"
+	Number   = "number"
+	Comment  = "comment"
+	Literal  = "literal"
+	Variable = "variable"
+	Key      = "key"
+	Tag      = "tag"
+	Attr     = "attr"
+)
+
+// ClassPrefix is prepended to token classes in the HTML output.
+const ClassPrefix = "hl-"
+
+type lexer func(s *scanner)
+
+var languages = map[string]lexer{}
+
+func register(l lexer, names ...string) {
+	for _, n := range names {
+		languages[n] = l
+	}
+}
+
+// Supported reports whether lang, e.g. "go" or "yaml", can be highlighted.
+func Supported(lang string) bool {
+	_, ok := languages[strings.ToLower(lang)]
+	return ok
+}
+
+// Highlight returns code as escaped HTML with class-based spans. Unknown
+// languages are only escaped and reported with ok == false.
+func Highlight(code, lang string) (out string, ok bool) {
+	lex, ok := languages[strings.ToLower(lang)]
+	if !ok {
+		return html.EscapeString(code), false
+	}
+
+	s := &scanner{src: code}
+	lex(s)
+	return s.out.String(), true
+}
+
+// scanner walks the source and writes escaped tokens.
+type scanner struct {
+	src string
+	pos int
+	out strings.Builder
+}
+
+func (s *scanner) done() bool {
+	return s.pos >= len(s.src)
+}
+
+func (s *scanner) rest() string {
+	return s.src[s.pos:]
+}
+
+// match returns the token re matches at the current position, if any.
+func (s *scanner) match(re *regexp.Regexp) string {
+	loc := re.FindStringIndex(s.rest())
+	if loc == nil || loc[0] != 0 || loc[1] == 0 {
+		return ""
+	}
+	return s.rest()[:loc[1]]
+}
+
+func (s *scanner) emit(class, tok string) {
+	s.pos += len(tok)
+	if class == "" {
+		s.out.WriteString(html.EscapeString(tok))
+		return
+	}
+	s.out.WriteString(`<span class="` + ClassPrefix + class + `">`)
+	s.out.WriteString(html.EscapeString(tok))
+	s.out.WriteString(`</span>`)
+}
+
+// skip emits one rune without a class.
+func (s *scanner) skip() {
+	_, size := utf8.DecodeRuneInString(s.rest())
+	s.emit("", s.rest()[:size])
+}
+
+func words(list string) map[string]bool {
+	m := make(map[string]bool)
+	for _, w := range strings.Fields(list) {
+		m[w] = true
+	}
+	return m
+}
@@ -0,0 +1,121 @@
+package highlight_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/janmarkuslanger/ssgo/highlight"
+	"github.com/janmarkuslanger/ssgo/task"
+)
+
+func span(class, text string) string {
+	return `<span class="hl-` + class + `">` + text + `</span>`
+}
+
+func TestHighlight(t *testing.T) {
+	cases := []struct {
+		lang string
+		code string
+		want []string
+	}{
+		{lang: "go", code: "// hi\nfunc main() { fmt.Println(\"a<b\", 42, nil) }", want: []string{
+			span("comment", "// hi"), span("keyword", "func"), span("function", "Println"),
+			span("string", "&#34;a&lt;b&#34;"), span("number", "42"), span("literal", "nil"),
+		}},
+		{lang: "js", code: "const s = `t`; // c", want: []string{
+			span("keyword", "const"), span("string", "`t`"), span("comment", "// c"),
+		}},
+		{lang: "typescript", code: "interface A { x: number }", want: []string{
+			span("keyword", "interface"), span("type", "number"),
+		}},
+		{lang: "bash", code: "# install\nexport PATH=$HOME/bin\necho \"hi\" x#y", want: []string{
+			span("comment", "# install"), span("keyword", "export"), span("variable", "$HOME"),
+			span("string", "&#34;hi&#34;"), " x#y",
+		}},
+		{lang: "json", code: `{"name": "ssgo", "n": -1.5, "ok": true}`, want: []string{
+			span("key", "&#34;name&#34;"), span("string", "&#34;ssgo&#34;"), span("number", "-1.5"), span("literal", "true"),
+		}},
+		{lang: "yaml", code: "# c\ntitle: Hello\nurl: http://x.com\nok: yes", want: []string{
+			span("comment", "# c"), span("key", "title"), "http://x.com", span("literal", "yes"),
+		}},
+		{lang: "html", code: `<a href="/x">T &amp; U</a><script>let a = 1;</script>`, want: []string{
+			span("tag", "&lt;a"), span("attr", "href"), span("string", "&#34;/x&#34;"),
+			span("literal", "&amp;amp;"), span("keyword", "let"), span("tag", "&lt;/script"),
+		}},
+		{lang: "css", code: "@media (max-width: 600px) { a:hover, .btn { color: #fff !important; } }", want: []string{
+			span("keyword", "@media"), span("key", "max-width"), span("number", "600px"), span("tag", "a"),
+			span("keyword", ":hover"), span("attr", ".btn"), span("key", "color"), span("number", "#fff"),
+		}},
+		{lang: "SQL", code: "SELECT COUNT(*) FROM posts WHERE title = 'it''s' AND id = $1; -- c", want: []string{
+			span("keyword", "SELECT"), span("function", "COUNT"), span("string", "&#39;it&#39;&#39;s&#39;"),
+			span("variable", "$1"), span("comment", "-- c"),
+		}},
+	}
+
+	for _, c := range cases {
+		got, ok := highlight.Highlight(c.code, c.lang)
+		if !ok {
+			t.Errorf("%s: expected language to be supported", c.lang)
+		}
+		for _, want := range c.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: expected %q in\n%s", c.lang, want, got)
+			}
+		}
+	}
+}
+
+func TestHighlight_Unknown(t *testing.T) {
+	got, ok := highlight.Highlight("<x>", "brainfuck")
+	if ok || got != "&lt;x&gt;" {
+		t.Errorf("got %q, %v", got, ok)
+	}
+	if highlight.Supported("brainfuck") || !highlight.Supported("Go") {
+		t.Error("unexpected Supported result")
+	}
+}
+
+func TestHTML(t *testing.T) {
+	in := `<p>x</p><pre><code class="language-go">x := &quot;a&quot;</code></pre>` +
+		`<pre class="code"><code class="language-nope">a</code></pre>`
+	want := `<p>x</p><pre class="hl"><code class="language-go">x := ` + span("string", "&#34;a&#34;") + `</code></pre>` +
+		`<pre class="code"><code class="language-nope">a</code></pre>`
+
+	if got := highlight.HTML(in); got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+	if got := highlight.HTML(want); got != want {
+		t.Errorf("highlighted blocks should be left alone, got %s", got)
+	}
+}
+
+func TestTransform(t *testing.T) {
+	in := `<pre><code class="language-go">nil</code></pre>`
+
+	got, err := highlight.Transform("index.html", in)
+	if err != nil || !strings.Contains(got, span("literal", "nil")) {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if got, _ := highlight.Transform("feed.xml", in); got != in {
+		t.Errorf("non-HTML files should not change, got %q", got)
+	}
+}
+
+func TestThemeTask(t *testing.T) {
+	dir := t.TempDir()
+	if err := highlight.NewThemeTask(highlight.Dark, "assets/highlight.css").Run(task.TaskContext{OutputDir: dir}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	css, err := os.ReadFile(filepath.Join(dir, "assets", "highlight.css"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"pre.hl {", "background: #0d1117;", ".hl .hl-keyword {\n  color: #ff7b72;"} {
+		if !strings.Contains(string(css), want) {
+			t.Errorf("expected %q in\n%s", want, css)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package highlight
+
+import "strings"
+
+var (
+	htmlComment   = re(`<!--[\s\S]*?(?:-->|\z)`)
+	htmlDirective = re(`<![^>]*>?`)
+	htmlTagOpen   = re(`</?[A-Za-z][\w:-]*`)
+	htmlTagClose  = re(`/?>`)
+	htmlAttr      = re(`[^\s"'<>/=]+`)
+	htmlValue     = re(`"[^"]*"?|'[^']*'?|[^\s"'<>=` + "`" + `]+`)
+	htmlEntity    = re(`&(?:#\d+|#x[0-9a-fA-F]+|\w+);`)
+	htmlText      = re(`[^<&]+`)
+)
+
+// lexHTML highlights markup and the contents of script and style elements.
+func lexHTML(s *scanner) {
+	for !s.done() {
+		switch {
+		case s.match(htmlComment) != "":
+			s.emit(Comment, s.match(htmlComment))
+		case s.match(htmlDirective) != "":
+			s.emit(Keyword, s.match(htmlDirective))
+		case s.match(htmlTagOpen) != "":
+			name := s.match(htmlTagOpen)
+			s.emit(Tag, name)
+			lexHTMLTag(s)
+			if tag := strings.ToLower(name); tag == "<script" || tag == "<style" {
+				lexEmbedded(s, tag[1:])
+			}
+		case s.match(htmlEntity) != "":
+			s.emit(Literal, s.match(htmlEntity))
+		case s.match(htmlText) != "":
+			s.emit("", s.match(htmlText))
+		default:
+			s.skip()
+		}
+	}
+}
+
+// lexHTMLTag highlights attributes up to and including the closing >.
+func lexHTMLTag(s *scanner) {
+	for !s.done() {
+		switch {
+		case s.match(whitespace) != "":
+			s.emit("", s.match(whitespace))
+		case s.match(htmlTagClose) != "":
+			s.emit(Tag, s.match(htmlTagClose))
+			return
+		case strings.HasPrefix(s.rest(), "="):
+			s.emit("", "=")
+			if s.match(htmlValue) != "" {
+				s.emit(String, s.match(htmlValue))
+			}
+		case s.match(htmlAttr) != "":
+			s.emit(Attr, s.match(htmlAttr))
+		case strings.HasPrefix(s.rest(), "<"):
+			return
+		default:
+			s.skip()
+		}
+	}
+}
+
+// lexEmbedded highlights the body of a script or style element with the
+// JavaScript or CSS lexer.
+func lexEmbedded(s *scanner, tag string) {
+	end := strings.Index(strings.ToLower(s.rest()), "</"+tag)
+	if end < 0 {
+		end = len(s.rest())
+	}
+
+	lang := "js"
+	if tag == "style" {
+		lang = "css"
+	}
+	inner := &scanner{src: s.rest()[:end]}
+	languages[lang](inner)
+	s.out.WriteString(inner.out.String())
+	s.pos += end
+}
+
+var (
+	cssAtRule    = re(`@[\w-]+`)
+	cssSelector  = re(`[.#][\w-]+`)
+	cssPseudo    = re(`::?[\w-]+`)
+	cssIdent     = re(`-?[A-Za-z_][\w-]*`)
+	cssColor     = re(`#[0-9a-fA-F]{3,8}\b`)
+	cssNumber    = re(`-?(?:\d+\.?\d*|\.\d+)(?:%|[A-Za-z]+)?`)
+	cssImportant = re(`!\s*important`)
+)
+
+// lexCSS highlights selectors outside of blocks and declarations inside.
+func lexCSS(s *scanner) {
+	depth := 0
+	for !s.done() {
+		switch {
+		case s.match(whitespace) != "":
+			s.emit("", s.match(whitespace))
+		case s.match(blockComment) != "":
+			s.emit(Comment, s.match(blockComment))
+		case s.match(doubleString) != "":
+			s.emit(String, s.match(doubleString))
+		case s.match(singleString) != "":
+			s.emit(String, s.match(singleString))
+		case s.match(cssAtRule) != "":
+			s.emit(Keyword, s.match(cssAtRule))
+		case strings.HasPrefix(s.rest(), "{"):
+			depth++
+			s.emit("", "{")
+		case strings.HasPrefix(s.rest(), "}"):
+			depth = max(0, depth-1)
+			s.emit("", "}")
+		case depth == 0 || opensBlock(s.rest()):
+			lexSelector(s)
+		default:
+			lexDeclaration(s)
+		}
+	}
+}
+
+// opensBlock reports whether css up to the next ; or } starts a block,
+// i.e. is a selector nested in an at-rule.
+func opensBlock(css string) bool {
+	i := strings.IndexAny(css, "{;}")
+	return i >= 0 && css[i] == '{'
+}
+
+func lexSelector(s *scanner) {
+	switch {
+	case s.match(cssNumber) != "":
+		s.emit(Number, s.match(cssNumber))
+	case s.match(cssSelector) != "":
+		s.emit(Attr, s.match(cssSelector))
+	case s.match(cssPseudo) != "":
+		s.emit(Keyword, s.match(cssPseudo))
+	case s.match(cssIdent) != "":
+		tok := s.match(cssIdent)
+		if strings.HasPrefix(s.rest()[len(tok):], ": ") {
+			// A feature in a media query such as (max-width: 600px).
+			s.emit(Key, tok)
+			return
+		}
+		s.emit(Tag, tok)
+	default:
+		s.skip()
+	}
+}
+
+func lexDeclaration(s *scanner) {
+	switch {
+	case s.match(cssColor) != "":
+		s.emit(Number, s.match(cssColor))
+	case s.match(cssNumber) != "":
+		s.emit(Number, s.match(cssNumber))
+	case s.match(cssImportant) != "":
+		s.emit(Keyword, s.match(cssImportant))
+	case s.match(cssIdent) != "":
+		tok := s.match(cssIdent)
+		after := strings.TrimLeft(s.rest()[len(tok):], " \t")
+		switch {
+		case strings.HasPrefix(after, ":"):
+			s.emit(Key, tok)
+		case strings.HasPrefix(after, "("):
+			s.emit(Function, tok)
+		default:
+			s.emit(Literal, tok)
+		}
+	default:
+		s.skip()
+	}
+}
+
+func init() {
+	register(lexHTML, "html", "htm", "xml", "svg")
+	register(lexCSS, "css")
+}
@@ -0,0 +1,208 @@
+package highlight
+
+import (
+	"regexp"
+	"strings"
+)
+
+// rule matches a token at the current position. Identifier rules are
+// classified by the language's word lists instead of a fixed class.
+type rule struct {
+	re    *regexp.Regexp
+	class string
+	ident bool
+	// keyable strings and words become keys when followed by a colon.
+	keyable bool
+}
+
+// ruleLanguage covers languages that can be tokenised without state.
+type ruleLanguage struct {
+	rules      []rule
+	keywords   map[string]bool
+	types      map[string]bool
+	literals   map[string]bool
+	ignoreCase bool
+}
+
+func re(pattern string) *regexp.Regexp {
+	return regexp.MustCompile(`\A(?:` + pattern + `)`)
+}
+
+var (
+	whitespace   = re(`\s+`)
+	lineComment  = re(`//[^\n]*`)
+	blockComment = re(`/\*[\s\S]*?(?:\*/|\z)`)
+	doubleString = re(`"(?:[^"\\\n]|\\.)*"?`)
+	singleString = re(`'(?:[^'\\\n]|\\.)*'?`)
+	number       = re(`0[xX][0-9a-fA-F_]+|-?\d[\d_]*(?:\.\d+)?(?:[eE][+-]?\d+)?`)
+	identifier   = re(`[A-Za-z_$][\w$]*`)
+)
+
+func (l ruleLanguage) lex(s *scanner) {
+	for !s.done() {
+		if tok := s.match(whitespace); tok != "" {
+			s.emit("", tok)
+			continue
+		}
+
+		matched := false
+		for _, r := range l.rules {
+			tok := s.match(r.re)
+			if tok == "" {
+				continue
+			}
+			matched = true
+
+			class := r.class
+			if r.ident {
+				class = l.classify(s, tok)
+			}
+			if r.keyable && isKey(tok, s.rest()[len(tok):]) {
+				class = Key
+			}
+			s.emit(class, tok)
+			break
+		}
+		if !matched {
+			s.skip()
+		}
+	}
+}
+
+// isKey reports whether tok is followed by a colon. Unquoted words need a
+// blank after the colon so that values like http://example.com stay intact.
+func isKey(tok, after string) bool {
+	after = strings.TrimLeft(after, " \t")
+	if !strings.HasPrefix(after, ":") {
+		return false
+	}
+	if strings.HasPrefix(tok, `"`) || strings.HasPrefix(tok, "'") {
+		return true
+	}
+	return len(after) == 1 || strings.ContainsAny(after[1:2], " \t\r\n")
+}
+
+func (l ruleLanguage) classify(s *scanner, tok string) string {
+	word := tok
+	if l.ignoreCase {
+		word = strings.ToLower(tok)
+	}
+
+	switch {
+	case l.keywords[word]:
+		return Keyword
+	case l.types[word]:
+		return Type
+	case l.literals[word]:
+		return Literal
+	case strings.HasPrefix(strings.TrimLeft(s.rest()[len(tok):], " \t"), "("):
+		return Function
+	}
+	return ""
+}
+
+func init() {
+	register(ruleLanguage{
+		rules: []rule{
+			{re: lineComment, class: Comment},
+			{re: blockComment, class: Comment},
+			{re: doubleString, class: String},
+			{re: re("`[^`]*`?"), class: String},
+			{re: singleString, class: String},
+			{re: number, class: Number},
+			{re: re(`[A-Za-z_]\w*`), ident: true},
+		},
+		keywords: words(`break case chan const continue default defer else fallthrough for func go goto if
+			import interface map package range return select struct switch type var`),
+		types: words(`any bool byte comparable complex64 complex128 error float32 float64 int int8 int16
+			int32 int64 rune string uint uint8 uint16 uint32 uint64 uintptr`),
+		literals: words(`true false nil iota`),
+	}.lex, "go", "golang")
+
+	jsKeywords := `async await break case catch class const continue debugger default delete do else
+		export extends finally for from function get if import in instanceof let new of return set static
+		super switch this throw try typeof var void while with yield`
+	jsRules := []rule{
+		{re: lineComment, class: Comment},
+		{re: blockComment, class: Comment},
+		{re: doubleString, class: String},
+		{re: singleString, class: String},
+		{re: re("`(?:[^`\\\\]|\\\\.)*`?"), class: String},
+		{re: number, class: Number},
+		{re: identifier, ident: true},
+	}
+	jsLiterals := words(`true false null undefined NaN Infinity`)
+
+	register(ruleLanguage{
+		rules:    jsRules,
+		keywords: words(jsKeywords),
+		literals: jsLiterals,
+	}.lex, "js", "javascript", "jsx", "mjs")
+
+	register(ruleLanguage{
+		rules: jsRules,
+		keywords: words(jsKeywords + ` abstract as declare enum implements infer interface is keyof module
+			namespace private protected public readonly satisfies type`),
+		types:    words(`any bigint boolean never number object string symbol unknown void`),
+		literals: jsLiterals,
+	}.lex, "ts", "typescript", "tsx")
+
+	register(ruleLanguage{
+		rules: []rule{
+			{re: re(`(?:^|\B)#[^\n]*`), class: Comment},
+			{re: doubleString, class: String},
+			{re: re(`'[^']*'?`), class: String},
+			{re: re(`\$(?:\{[^}\n]*\}?|[A-Za-z_]\w*|[0-9@#?*$!-])`), class: Variable},
+			{re: re(`[A-Za-z0-9_./:@%+=,-][^\s;|&<>()$"'` + "`" + `]*`), ident: true},
+		},
+		keywords: words(`if then else elif fi for while until do done case esac in function return
+			select time break continue export local readonly declare`),
+		literals: words(`true false`),
+	}.lex, "sh", "shell", "bash", "zsh", "console")
+
+	register(ruleLanguage{
+		rules: []rule{
+			{re: doubleString, class: String, keyable: true},
+			{re: number, class: Number},
+			{re: re(`[A-Za-z]+`), ident: true},
+		},
+		literals: words(`true false null`),
+	}.lex, "json", "jsonc")
+
+	register(ruleLanguage{
+		rules: []rule{
+			{re: re(`(?:^|\B)#[^\n]*`), class: Comment},
+			{re: re(`(?m)^(?:---|\.\.\.)$`), class: Keyword},
+			{re: doubleString, class: String, keyable: true},
+			{re: re(`'(?:[^']|'')*'?`), class: String, keyable: true},
+			{re: re(`[&*][\w-]+`), class: Variable},
+			{re: re(`!!?[\w/]+`), class: Type},
+			{re: re(`(?m)[|>][-+]?\d*$`), class: Keyword},
+			{re: number, class: Number},
+			{re: re(`[A-Za-z_~][\w.-]*`), ident: true, keyable: true},
+		},
+		literals:   words(`true false null yes no on off ~`),
+		ignoreCase: true,
+	}.lex, "yaml", "yml")
+
+	register(ruleLanguage{
+		rules: []rule{
+			{re: re(`--[^\n]*`), class: Comment},
+			{re: blockComment, class: Comment},
+			{re: re(`'(?:[^']|'')*'?`), class: String},
+			{re: re(`"[^"]*"?|` + "`[^`]*`?"), class: Variable},
+			{re: re(`\$\d+|:[A-Za-z_]\w*|\?`), class: Variable},
+			{re: number, class: Number},
+			{re: re(`[A-Za-z_]\w*`), ident: true},
+		},
+		keywords: words(`add all alter and as asc begin between by case check column commit constraint
+			create cross default delete desc distinct drop else end exists foreign from full group having
+			if in index inner insert into is join key left like limit not offset on or order outer primary
+			references returning right rollback select set table then transaction union unique update using
+			values view when where with`),
+		types: words(`bigint bigserial blob boolean bool char date datetime decimal double float int integer
+			interval json jsonb numeric real serial smallint text time timestamp timestamptz uuid varchar`),
+		literals:   words(`null true false`),
+		ignoreCase: true,
+	}.lex, "sql", "psql", "mysql", "sqlite")
+}
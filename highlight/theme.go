@@ -0,0 +1,99 @@
+package highlight
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/janmarkuslanger/ssgo/task"
+)
+
+// Theme maps token classes to colors for the generated stylesheet.
+type Theme struct {
+	Background string
+	Foreground string
+	Colors     map[string]string
+}
+
+var (
+	Light = Theme{
+		Background: "#f6f8fa",
+		Foreground: "#24292f",
+		Colors: map[string]string{
+			Keyword:  "#cf222e",
+			Type:     "#953800",
+			Function: "#8250df",
+			String:   "#0a3069",
+			Number:   "#0550ae",
+			Comment:  "#6e7781",
+			Literal:  "#0550ae",
+			Variable: "#953800",
+			Key:      "#116329",
+			Tag:      "#116329",
+			Attr:     "#0550ae",
+		},
+	}
+
+	Dark = Theme{
+		Background: "#0d1117",
+		Foreground: "#c9d1d9",
+		Colors: map[string]string{
+			Keyword:  "#ff7b72",
+			Type:     "#ffa657",
+			Function: "#d2a8ff",
+			String:   "#a5d6ff",
+			Number:   "#79c0ff",
+			Comment:  "#8b949e",
+			Literal:  "#79c0ff",
+			Variable: "#ffa657",
+			Key:      "#7ee787",
+			Tag:      "#7ee787",
+			Attr:     "#79c0ff",
+		},
+	}
+)
+
+// CSS returns the stylesheet for pre.hl blocks and their token spans.
+func (t Theme) CSS() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pre.hl {\n  background: %s;\n  color: %s;\n  padding: 1em;\n  overflow-x: auto;\n}\n", t.Background, t.Foreground)
+
+	classes := make([]string, 0, len(t.Colors))
+	for class := range t.Colors {
+		classes = append(classes, class)
+	}
+	sort.Strings(classes)
+
+	for _, class := range classes {
+		fmt.Fprintf(&b, ".hl .%s%s {\n  color: %s;\n}\n", ClassPrefix, class, t.Colors[class])
+		if class == Comment {
+			fmt.Fprintf(&b, ".hl .%s%s {\n  font-style: italic;\n}\n", ClassPrefix, class)
+		}
+	}
+	return b.String()
+}
+
+// NewThemeTask writes the stylesheet of theme to file below the output
+// directory, e.g. "assets/highlight.css".
+func NewThemeTask(theme Theme, file string) *ThemeTask {
+	return &ThemeTask{Theme: theme, File: file}
+}
+
+type ThemeTask struct {
+	Theme Theme
+	File  string
+}
+
+func (t *ThemeTask) Run(ctx task.TaskContext) error {
+	dest := filepath.Join(ctx.OutputDir, t.File)
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dest, []byte(t.Theme.CSS()), 0644)
+}
+
+func (t *ThemeTask) IsCritical() bool {
+	return false
+}
@@ -0,0 +1,58 @@
+package highlight
+
+import (
+	"html"
+	"html/template"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	codeBlockPattern = regexp.MustCompile(`(?s)<pre([^>]*)>\s*<code([^>]*)\bclass="([^"]*)"([^>]*)>(.*?)</code>\s*</pre>`)
+	languagePattern  = regexp.MustCompile(`(?:^|\s)(?:language|lang)-([\w+#-]+)`)
+	preClassPattern  = regexp.MustCompile(`\bclass="([^"]*)"`)
+)
+
+// Block highlights code as a <pre class="hl"><code class="language-x">
+// block, e.g. for the highlight template function.
+func Block(code, lang string) template.HTML {
+	out, _ := Highlight(code, lang)
+	return template.HTML(`<pre class="hl"><code class="language-` + html.EscapeString(lang) + `">` + out + `</code></pre>`)
+}
+
+// HTML highlights every <pre><code class="language-x"> block in rendered
+// HTML, such as fenced code blocks from markdown. Blocks in unsupported
+// languages are left unchanged.
+func HTML(content string) string {
+	return codeBlockPattern.ReplaceAllStringFunc(content, func(block string) string {
+		m := codeBlockPattern.FindStringSubmatch(block)
+		lang := languagePattern.FindStringSubmatch(m[3])
+		if lang == nil || strings.Contains(m[5], "<span") {
+			return block
+		}
+
+		out, ok := Highlight(html.UnescapeString(m[5]), lang[1])
+		if !ok {
+			return block
+		}
+
+		return `<pre` + addClass(m[1], "hl") + `><code` + m[2] + `class="` + m[3] + `"` + m[4] + `>` + out + `</code></pre>`
+	})
+}
+
+// Transform applies HTML to .html files and returns other files unchanged.
+// It matches builder.Transform.
+func Transform(file, content string) (string, error) {
+	if filepath.Ext(file) != ".html" {
+		return content, nil
+	}
+	return HTML(content), nil
+}
+
+func addClass(attrs, class string) string {
+	if m := preClassPattern.FindStringSubmatchIndex(attrs); m != nil {
+		return attrs[:m[3]] + " " + class + attrs[m[3]:]
+	}
+	return attrs + ` class="` + class + `"`
+}
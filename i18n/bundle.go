@@ -0,0 +1,210 @@
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/janmarkuslanger/ssgo/data"
+)
+
+var pluralForms = []string{"zero", "one", "two", "few", "many", "other"}
+
+// PluralRule returns the plural form ("zero", "one", "two", "few", "many" or
+// "other") to use for a count.
+type PluralRule func(count int) string
+
+// DefaultPluralRule covers languages like English and German.
+func DefaultPluralRule(count int) string {
+	if count == 1 {
+		return "one"
+	}
+	return "other"
+}
+
+func NewBundle(fallback string) *Bundle {
+	return &Bundle{
+		Fallback: fallback,
+		messages: make(map[string]map[string]any),
+	}
+}
+
+// Bundle holds the translations of all languages. Messages are either strings
+// or plural maps with keys such as "one" and "other". Placeholders are written
+// as {count} or {name}.
+type Bundle struct {
+	// Fallback is the language used when a key is missing in a language.
+	Fallback string
+	// PluralRules overrides DefaultPluralRule per language code.
+	PluralRules map[string]PluralRule
+
+	messages map[string]map[string]any
+}
+
+// LoadBundle reads one file per language from dir, named by language code,
+// e.g. en.yaml or de.json. Nested keys are joined with ".".
+func LoadBundle(dir string, fallback string) (*Bundle, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read translations: %w", err)
+	}
+
+	b := NewBundle(fallback)
+	for _, e := range entries {
+		if e.IsDir() || !data.IsSupported(e.Name()) {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read translation file %s: %w", path, err)
+		}
+
+		value, err := data.Parse(path, content)
+		if err != nil {
+			return nil, err
+		}
+
+		messages, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("translation file %s must contain a map", path)
+		}
+
+		b.Add(strings.TrimSuffix(e.Name(), filepath.Ext(e.Name())), messages)
+	}
+
+	return b, nil
+}
+
+// Add merges messages into a language. Nested maps that are not plural
+// messages are flattened with "." separated keys.
+func (b *Bundle) Add(lang string, messages map[string]any) {
+	if b.messages[lang] == nil {
+		b.messages[lang] = make(map[string]any)
+	}
+	flatten(b.messages[lang], "", messages)
+}
+
+// T translates key into lang. A leading numeric argument is the count used to
+// pick the plural form and fills {count}; the remaining arguments are
+// name/value pairs for other placeholders.
+func (b *Bundle) T(lang string, key string, args ...any) string {
+	msg, ok := b.lookup(lang, key)
+	if !ok {
+		return key
+	}
+
+	count, hasCount := 0, false
+	if len(args) > 0 {
+		if n, ok := toInt(args[0]); ok {
+			count, hasCount = n, true
+			args = args[1:]
+		}
+	}
+
+	text := ""
+	switch m := msg.(type) {
+	case string:
+		text = m
+	case map[string]any:
+		text = b.plural(lang, m, count, hasCount)
+	}
+
+	if hasCount {
+		text = strings.ReplaceAll(text, "{count}", strconv.Itoa(count))
+	}
+	for i := 0; i+1 < len(args); i += 2 {
+		text = strings.ReplaceAll(text, "{"+fmt.Sprint(args[i])+"}", fmt.Sprint(args[i+1]))
+	}
+
+	return text
+}
+
+// Translator binds T to a language, e.g. for the T template function.
+func (b *Bundle) Translator(lang string) func(key string, args ...any) string {
+	return func(key string, args ...any) string {
+		return b.T(lang, key, args...)
+	}
+}
+
+func (b *Bundle) lookup(lang string, key string) (any, bool) {
+	if msg, ok := b.messages[lang][key]; ok {
+		return msg, true
+	}
+	msg, ok := b.messages[b.Fallback][key]
+	return msg, ok
+}
+
+// plural picks the form for count, or "other" when no count was passed.
+func (b *Bundle) plural(lang string, forms map[string]any, count int, hasCount bool) string {
+	if !hasCount {
+		s, _ := forms["other"].(string)
+		return s
+	}
+
+	rule := PluralRule(DefaultPluralRule)
+	if r, ok := b.PluralRules[lang]; ok {
+		rule = r
+	}
+
+	if count == 0 {
+		if s, ok := forms["zero"].(string); ok {
+			return s
+		}
+	}
+	if s, ok := forms[rule(count)].(string); ok {
+		return s
+	}
+	s, _ := forms["other"].(string)
+	return s
+}
+
+func flatten(dst map[string]any, prefix string, src map[string]any) {
+	for k, v := range src {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+
+		m, ok := v.(map[string]any)
+		if !ok || isPlural(m) {
+			if ok {
+				dst[key] = m
+			} else {
+				dst[key] = fmt.Sprint(v)
+			}
+			continue
+		}
+		flatten(dst, key, m)
+	}
+}
+
+func isPlural(m map[string]any) bool {
+	if _, ok := m["other"]; !ok {
+		return false
+	}
+	for k := range m {
+		if !slices.Contains(pluralForms, k) {
+			return false
+		}
+	}
+	return true
+}
+
+func toInt(v any) (int, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return int(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return int(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return int(rv.Float()), true
+	}
+	return 0, false
+}
@@ -0,0 +1,122 @@
+package i18n_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/janmarkuslanger/ssgo/i18n"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+}
+
+func TestLoadBundle(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "en.yaml", `
+nav:
+  home: Home
+posts:
+  zero: No posts
+  one: One post
+  other: "{count} posts"
+greeting: Hello {name}
+only_en: English only
+`)
+	writeFile(t, dir, "de.json", `{
+  "nav": {"home": "Startseite"},
+  "posts": {"one": "Ein Beitrag", "other": "{count} Beiträge"},
+  "greeting": "Hallo {name}"
+}`)
+	writeFile(t, dir, "README.md", "ignored")
+
+	b, err := i18n.LoadBundle(dir, "en")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		lang string
+		key  string
+		args []any
+		want string
+	}{
+		{lang: "en", key: "nav.home", want: "Home"},
+		{lang: "de", key: "nav.home", want: "Startseite"},
+		{lang: "en", key: "posts", args: []any{0}, want: "No posts"},
+		{lang: "en", key: "posts", args: []any{1}, want: "One post"},
+		{lang: "en", key: "posts", args: []any{5}, want: "5 posts"},
+		{lang: "en", key: "posts", want: "{count} posts"},
+		{lang: "en", key: "posts", args: []any{int32(1)}, want: "One post"},
+		{lang: "en", key: "posts", args: []any{uint(5)}, want: "5 posts"},
+		{lang: "en", key: "posts", args: []any{uint8(0)}, want: "No posts"},
+		{lang: "en", key: "posts", args: []any{int16(2)}, want: "2 posts"},
+		{lang: "de", key: "posts", args: []any{0}, want: "0 Beiträge"},
+		{lang: "de", key: "posts", args: []any{1}, want: "Ein Beitrag"},
+		{lang: "de", key: "greeting", args: []any{"name", "Jan"}, want: "Hallo Jan"},
+		{lang: "de", key: "only_en", want: "English only"},
+		{lang: "de", key: "missing.key", want: "missing.key"},
+	}
+
+	for _, c := range cases {
+		if got := b.T(c.lang, c.key, c.args...); got != c.want {
+			t.Errorf("T(%q, %q, %v): got %q, want %q", c.lang, c.key, c.args, got, c.want)
+		}
+	}
+}
+
+func TestLoadBundle_Errors(t *testing.T) {
+	if _, err := i18n.LoadBundle(filepath.Join(t.TempDir(), "missing"), "en"); err == nil {
+		t.Error("expected error for missing dir")
+	}
+
+	dir := t.TempDir()
+	writeFile(t, dir, "en.json", `["not", "a", "map"]`)
+	if _, err := i18n.LoadBundle(dir, "en"); err == nil {
+		t.Error("expected error for non-map translation file")
+	}
+
+	dir = t.TempDir()
+	writeFile(t, dir, "en.json", `{"a": }`)
+	if _, err := i18n.LoadBundle(dir, "en"); err == nil {
+		t.Error("expected parse error")
+	}
+}
+
+func TestBundle_PluralRules(t *testing.T) {
+	b := i18n.NewBundle("pl")
+	b.Add("pl", map[string]any{
+		"files": map[string]any{"one": "1 plik", "few": "{count} pliki", "other": "{count} plików"},
+	})
+	b.PluralRules = map[string]i18n.PluralRule{
+		"pl": func(n int) string {
+			if n == 1 {
+				return "one"
+			}
+			if n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14) {
+				return "few"
+			}
+			return "other"
+		},
+	}
+
+	if got := b.T("pl", "files", 3); got != "3 pliki" {
+		t.Errorf("unexpected plural: %q", got)
+	}
+	if got := b.T("pl", "files", 5); got != "5 plików" {
+		t.Errorf("unexpected plural: %q", got)
+	}
+}
+
+func TestBundle_Translator(t *testing.T) {
+	b := i18n.NewBundle("en")
+	b.Add("de", map[string]any{"hello": "Hallo"})
+
+	if got := b.Translator("de")("hello"); got != "Hallo" {
+		t.Errorf("unexpected translation: %q", got)
+	}
+}
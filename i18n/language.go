@@ -0,0 +1,67 @@
+package i18n
+
+import (
+	"html/template"
+	"strings"
+)
+
+type Language struct {
+	Code string
+	Name string
+	// BaseURL serves the language from its own domain, e.g. https://example.de/.
+	// When empty, the language lives below /<code>/ of the site's base URL.
+	BaseURL string
+}
+
+// Alternate is a translated version of a page, used for hreflang links.
+type Alternate struct {
+	Lang string
+	URL  string
+}
+
+var redirectTemplate = template.Must(template.New("redirect").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<title>{{ .Default }}</title>
+{{ range .Alternates }}<link rel="alternate" hreflang="{{ .Lang }}" href="{{ .URL }}">
+{{ end }}<link rel="alternate" hreflang="x-default" href="{{ .Default }}">
+<script>
+(function () {
+  var targets = {{ .Targets }};
+  var prefs = navigator.languages || [navigator.language || ""];
+  for (var i = 0; i < prefs.length; i++) {
+    var code = prefs[i].toLowerCase();
+    var target = targets[code] || targets[code.split("-")[0]];
+    if (target) { location.replace(target); return; }
+  }
+  location.replace({{ .Default }});
+})();
+</script>
+<meta http-equiv="refresh" content="1; url={{ .Default }}">
+</head>
+<body><a href="{{ .Default }}">{{ .Default }}</a></body>
+</html>
+`))
+
+// RedirectPage returns an HTML page that sends visitors to the translation
+// matching their browser language, falling back to the first alternate.
+func RedirectPage(alternates []Alternate) string {
+	if len(alternates) == 0 {
+		return ""
+	}
+
+	targets := make(map[string]string, len(alternates))
+	for _, a := range alternates {
+		targets[strings.ToLower(a.Lang)] = a.URL
+	}
+
+	var b strings.Builder
+	redirectTemplate.Execute(&b, map[string]any{
+		"Alternates": alternates,
+		"Targets":    targets,
+		"Default":    alternates[0].URL,
+	})
+	return b.String()
+}
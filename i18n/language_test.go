@@ -0,0 +1,33 @@
+package i18n_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/janmarkuslanger/ssgo/i18n"
+)
+
+func TestRedirectPage(t *testing.T) {
+	out := i18n.RedirectPage([]i18n.Alternate{
+		{Lang: "en", URL: "/en/"},
+		{Lang: "de", URL: "https://example.de/"},
+	})
+
+	for _, want := range []string{
+		`<link rel="alternate" hreflang="en" href="/en/">`,
+		`<link rel="alternate" hreflang="de" href="https://example.de/">`,
+		`<link rel="alternate" hreflang="x-default" href="/en/">`,
+		`<meta http-equiv="refresh" content="1; url=/en/">`,
+		`"de":"https://example.de/"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestRedirectPage_Empty(t *testing.T) {
+	if out := i18n.RedirectPage(nil); out != "" {
+		t.Errorf("expected empty output, got %q", out)
+	}
+}
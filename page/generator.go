@@ -2,14 +2,28 @@ package page
 
 import (
 	"errors"
+	"fmt"
+	"maps"
 	"sync"
 
+	"github.com/janmarkuslanger/ssgo/i18n"
 	"github.com/janmarkuslanger/ssgo/rendering"
+	"github.com/janmarkuslanger/ssgo/summary"
+	"github.com/janmarkuslanger/ssgo/urls"
 )
 
 type PagePayload struct {
 	Params map[string]string
 	Path   string
+	Site   map[string]any
+	// Publish filters unpublished entries from listings, feeds and sitemaps.
+	Publish PublishPolicy
+	// URLs builds links that match the site-wide URL style.
+	URLs urls.Config
+	// BaseURL is the site root, e.g. https://example.com/docs/.
+	BaseURL string
+	// Lang is the language code of the page on multilingual sites.
+	Lang string
 }
 
 type Config struct {
@@ -17,32 +31,97 @@ type Config struct {
 	Pattern  string
 	GetData  func(payload PagePayload) map[string]any
 	GetPaths func() []string
+	// GetTemplate overrides Template per page when it returns a non-empty value.
+	GetTemplate func(payload PagePayload) string
+	// GetRenderer overrides Renderer per page when it returns a non-nil value,
+	// e.g. to pick a different layout set.
+	GetRenderer func(payload PagePayload) rendering.Renderer
 	// MaxWorkers controls parallel page generation. Values <= 1 run sequentially.
 	MaxWorkers int
 	Renderer   rendering.Renderer
+	// Outputs renders every page once per format. When empty, the page is
+	// rendered once with Template and Renderer.
+	Outputs []OutputFormat
+	// Summary adds Summary, Truncated, WordCount and ReadingTime to the data
+	// of every page, computed from its HTML content.
+	Summary *summary.Options
+	// Languages limits the generator to these language codes on multilingual
+	// sites; all languages by default. The empty code "" writes the pages
+	// once at the site root, e.g. for a root index or a sitemap.
+	Languages []string
 }
 
 type Generator struct {
 	Config Config
+	// Site holds the site data shared by all pages, usually set by the builder.
+	Site map[string]any
+	// Publish is passed to GetData, usually set by the builder.
+	Publish PublishPolicy
+	// URLs is passed to GetData, usually set by the builder.
+	URLs urls.Config
+	// BaseURL is passed to GetData and templates, usually set by the builder.
+	BaseURL string
+	// Lang is the language the pages are generated in. Its BaseURL, when set,
+	// replaces BaseURL for page URLs. Usually set by the builder.
+	Lang i18n.Language
+	// Languages lists all site languages for hreflang alternates.
+	Languages []i18n.Language
+	// Translate backs the T template function.
+	Translate func(key string, args ...any) string
+}
+
+func (g Generator) Formats() []OutputFormat {
+	return formats(g.Config.Outputs)
 }
 
 func (g Generator) GeneratePageInstance(path string) Page {
 	data := make(map[string]any)
 	params := ExtractParams(g.Config.Pattern, path)
+	payload := PagePayload{
+		Path:    path,
+		Params:  params,
+		Site:    g.Site,
+		Publish: g.Publish,
+		URLs:    g.URLs,
+		BaseURL: g.BaseURL,
+		Lang:    g.Lang.Code,
+	}
 
 	if g.Config.GetData != nil {
-		data = g.Config.GetData(PagePayload{
-			Path:   path,
-			Params: params,
-		})
+		data = g.Config.GetData(payload)
+	}
+	if g.Config.Summary != nil && data != nil {
+		data = maps.Clone(data)
+		summary.Apply(data, *g.Config.Summary)
+	}
+
+	tmpl := g.Config.Template
+	if g.Config.GetTemplate != nil {
+		if t := g.Config.GetTemplate(payload); t != "" {
+			tmpl = t
+		}
+	}
+
+	renderer := g.Config.Renderer
+	if g.Config.GetRenderer != nil {
+		if r := g.Config.GetRenderer(payload); r != nil {
+			renderer = r
+		}
 	}
 
 	return Page{
-		Path:     path,
-		Params:   params,
-		Data:     data,
-		Template: g.Config.Template,
-		Renderer: g.Config.Renderer,
+		Path:      path,
+		Params:    params,
+		Data:      data,
+		Site:      g.Site,
+		Template:  tmpl,
+		Renderer:  renderer,
+		Outputs:   g.Config.Outputs,
+		URLs:      g.URLs,
+		BaseURL:   g.BaseURL,
+		Lang:      g.Lang,
+		Languages: g.Languages,
+		Translate: g.Translate,
 	}
 }
 
@@ -62,7 +141,7 @@ func (g Generator) GeneratePageInstances() ([]Page, error) {
 		for i, path := range paths {
 			pages[i] = g.GeneratePageInstance(path)
 		}
-		return pages, nil
+		return pages, validateTemplates(pages)
 	}
 	if len(paths) < workers {
 		workers = len(paths)
@@ -92,5 +171,21 @@ func (g Generator) GeneratePageInstances() ([]Page, error) {
 	close(jobs)
 	wg.Wait()
 
-	return pages, nil
+	return pages, validateTemplates(pages)
+}
+
+func validateTemplates(pages []Page) error {
+	for _, p := range pages {
+		for _, o := range p.Formats() {
+			renderer, tmpl := p.resolve(o)
+			checker, ok := renderer.(rendering.TemplateChecker)
+			if !ok {
+				continue
+			}
+			if !checker.HasTemplate(tmpl) {
+				return fmt.Errorf("template %q for page %s does not exist", tmpl, p.Path)
+			}
+		}
+	}
+	return nil
 }
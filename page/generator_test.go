@@ -1,10 +1,13 @@
 package page_test
 
 import (
+	"html/template"
 	"strconv"
 	"testing"
 
 	"github.com/janmarkuslanger/ssgo/page"
+	"github.com/janmarkuslanger/ssgo/rendering"
+	"github.com/janmarkuslanger/ssgo/summary"
 )
 
 func TestGeneratorGeneratePages_MissingGetPaths(t *testing.T) {
@@ -140,3 +143,104 @@ func TestGeneratorGeneratePages_ConcurrencyKeepsOrder(t *testing.T) {
 		}
 	}
 }
+
+type checkingRenderer struct {
+	MockRenderer
+	templates map[string]bool
+}
+
+func (r checkingRenderer) HasTemplate(name string) bool {
+	return r.templates[name]
+}
+
+func TestGeneratorGeneratePages_PerPageTemplateAndRenderer(t *testing.T) {
+	landing := checkingRenderer{templates: map[string]bool{"landing.html": true}}
+	article := checkingRenderer{templates: map[string]bool{"article.html": true}}
+
+	c := page.Config{
+		Pattern: ":slug",
+		GetPaths: func() []string {
+			return []string{"home", "post"}
+		},
+		GetTemplate: func(p page.PagePayload) string {
+			if p.Params["slug"] == "home" {
+				return "landing.html"
+			}
+			return "article.html"
+		},
+		GetRenderer: func(p page.PagePayload) rendering.Renderer {
+			if p.Params["slug"] == "home" {
+				return landing
+			}
+			return nil
+		},
+		Renderer: article,
+	}
+	g := page.Generator{
+		Config: c,
+	}
+	p, err := g.GeneratePageInstances()
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if p[0].Template != "landing.html" || p[0].Renderer.(checkingRenderer).templates["landing.html"] != true {
+		t.Errorf("unexpected template or renderer for home: %q", p[0].Template)
+	}
+
+	if p[1].Template != "article.html" || p[1].Renderer.(checkingRenderer).templates["article.html"] != true {
+		t.Errorf("unexpected template or renderer for post: %q", p[1].Template)
+	}
+}
+
+func TestGeneratorGeneratePages_MissingTemplate(t *testing.T) {
+	c := page.Config{
+		GetPaths: func() []string {
+			return []string{"a", "b"}
+		},
+		GetTemplate: func(p page.PagePayload) string {
+			return p.Path + ".html"
+		},
+		Renderer:   checkingRenderer{templates: map[string]bool{"a.html": true}},
+		MaxWorkers: 2,
+	}
+	g := page.Generator{
+		Config: c,
+	}
+	_, err := g.GeneratePageInstances()
+
+	if err == nil {
+		t.Fatal("expected an error but got nil")
+	}
+
+	expectedErr := `template "b.html" for page b does not exist`
+	if err.Error() != expectedErr {
+		t.Errorf("unexpected error message: got %q, want %q", err.Error(), expectedErr)
+	}
+}
+
+func TestGeneratePageInstance_Summary(t *testing.T) {
+	shared := map[string]any{"content": "<p>one two <!--more--> three</p>", "WordCount": 99}
+	g := page.Generator{
+		Config: page.Config{
+			GetData: func(payload page.PagePayload) map[string]any { return shared },
+			Summary: &summary.Options{},
+		},
+	}
+
+	p := g.GeneratePageInstance("post")
+
+	if got := p.Data["Summary"]; got != template.HTML("<p>one two </p>") {
+		t.Errorf("unexpected summary: %q", got)
+	}
+	if p.Data["Truncated"] != true || p.Data["ReadingTime"] != 1 {
+		t.Errorf("unexpected data: %v", p.Data)
+	}
+	if p.Data["WordCount"] != 99 {
+		t.Errorf("existing keys should be kept, got %v", p.Data["WordCount"])
+	}
+	if _, ok := shared["Summary"]; ok {
+		t.Error("GetData's map should not be modified")
+	}
+}
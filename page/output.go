@@ -0,0 +1,26 @@
+package page
+
+import "github.com/janmarkuslanger/ssgo/rendering"
+
+// OutputFormat describes one representation of a page, e.g. HTML, JSON or
+// plain text. Empty Template and Renderer fall back to the page's own.
+type OutputFormat struct {
+	Name      string
+	Template  string
+	Renderer  rendering.Renderer
+	Extension string
+	MediaType string
+}
+
+var (
+	HTMLFormat = OutputFormat{Name: "html", Extension: ".html", MediaType: "text/html; charset=utf-8"}
+	JSONFormat = OutputFormat{Name: "json", Extension: ".json", MediaType: "application/json"}
+	TextFormat = OutputFormat{Name: "text", Extension: ".txt", MediaType: "text/plain; charset=utf-8"}
+)
+
+func formats(outputs []OutputFormat) []OutputFormat {
+	if len(outputs) == 0 {
+		return []OutputFormat{{}}
+	}
+	return outputs
+}
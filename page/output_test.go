@@ -0,0 +1,90 @@
+package page_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/janmarkuslanger/ssgo/page"
+	"github.com/janmarkuslanger/ssgo/rendering"
+)
+
+type echoRenderer struct{}
+
+func (r echoRenderer) Render(ctx rendering.RenderContext) (string, error) {
+	return ctx.Template, nil
+}
+
+func TestPage_Formats_Default(t *testing.T) {
+	p := page.Page{}
+	f := p.Formats()
+
+	if len(f) != 1 || f[0].Extension != "" {
+		t.Errorf("expected a single default format, got %v", f)
+	}
+}
+
+func TestPage_RenderOutput_FallsBackToPage(t *testing.T) {
+	p := page.Page{
+		Template: "page.html",
+		Renderer: echoRenderer{},
+	}
+
+	out, err := p.RenderOutput(page.HTMLFormat)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "page.html" {
+		t.Errorf("expected page template, got %q", out)
+	}
+
+	out, err = p.RenderOutput(page.OutputFormat{Template: "page.json"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "page.json" {
+		t.Errorf("expected format template, got %q", out)
+	}
+}
+
+func TestPage_RenderOutput_NoRenderer(t *testing.T) {
+	p := page.Page{}
+	_, err := p.RenderOutput(page.JSONFormat)
+
+	if err == nil || err.Error() != "no renderer set" {
+		t.Errorf("expected no renderer error, got %v", err)
+	}
+}
+
+type streamRenderer struct{}
+
+func (r streamRenderer) Render(ctx rendering.RenderContext) (string, error) {
+	return "", errors.New("Render must not be called")
+}
+
+func (r streamRenderer) RenderTo(ctx rendering.RenderContext, w io.Writer) error {
+	_, err := io.WriteString(w, "streamed "+ctx.Template)
+	return err
+}
+
+func TestPage_RenderOutputTo(t *testing.T) {
+	cases := []struct {
+		renderer rendering.Renderer
+		want     string
+	}{
+		{renderer: streamRenderer{}, want: "streamed page.html"},
+		{renderer: echoRenderer{}, want: "page.html"},
+	}
+
+	for _, c := range cases {
+		var b strings.Builder
+		p := page.Page{Template: "page.html", Renderer: c.renderer}
+		if err := p.RenderOutputTo(page.OutputFormat{}, &b); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if b.String() != c.want {
+			t.Errorf("got %q, want %q", b.String(), c.want)
+		}
+	}
+}
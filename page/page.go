@@ -2,25 +2,139 @@ package page
 
 import (
 	"errors"
+	"io"
 
+	"github.com/janmarkuslanger/ssgo/i18n"
 	"github.com/janmarkuslanger/ssgo/rendering"
+	"github.com/janmarkuslanger/ssgo/toc"
+	"github.com/janmarkuslanger/ssgo/urls"
 )
 
 type Page struct {
-	Path     string
-	Params   map[string]string
-	Data     map[string]any
-	Template string
-	Renderer rendering.Renderer
+	Path      string
+	Params    map[string]string
+	Data      map[string]any
+	Site      map[string]any
+	Template  string
+	Renderer  rendering.Renderer
+	Outputs   []OutputFormat
+	URLs      urls.Config
+	BaseURL   string
+	Lang      i18n.Language
+	Languages []i18n.Language
+	Translate func(key string, args ...any) string
+	// Debug is passed to renderers in debug mode, usually set by the builder.
+	Debug *rendering.Debug
+	// TOC receives the table of contents from the renderer, usually set by
+	// the builder.
+	TOC *toc.TOC
 }
 
 func (p Page) Render() (string, error) {
-	if p.Renderer == nil {
-		return "", errors.New("no renderer set")
+	return p.RenderOutput(OutputFormat{})
+}
+
+// Formats returns the output formats of the page. Pages without Outputs
+// have a single format using the page's Template and Renderer.
+func (p Page) Formats() []OutputFormat {
+	return formats(p.Outputs)
+}
+
+func (p Page) RenderOutput(o OutputFormat) (string, error) {
+	renderer, ctx, err := p.renderContext(o)
+	if err != nil {
+		return "", err
+	}
+
+	out, err := renderer.Render(ctx)
+	return out, p.decorate(err)
+}
+
+// RenderOutputTo renders an output format into w. Renderers implementing
+// rendering.StreamRenderer write directly, others are buffered.
+func (p Page) RenderOutputTo(o OutputFormat, w io.Writer) error {
+	renderer, ctx, err := p.renderContext(o)
+	if err != nil {
+		return err
+	}
+
+	if sr, ok := renderer.(rendering.StreamRenderer); ok {
+		return p.decorate(sr.RenderTo(ctx, w))
+	}
+
+	out, err := renderer.Render(ctx)
+	if err != nil {
+		return p.decorate(err)
+	}
+	_, err = io.WriteString(w, out)
+	return err
+}
+
+func (p Page) renderContext(o OutputFormat) (rendering.Renderer, rendering.RenderContext, error) {
+	renderer, tmpl := p.resolve(o)
+	if renderer == nil {
+		return nil, rendering.RenderContext{}, errors.New("no renderer set")
+	}
+
+	return renderer, rendering.RenderContext{
+		Data:        p.Data,
+		Site:        p.Site,
+		Template:    tmpl,
+		URL:         p.URL(o),
+		Canonical:   urls.AbsURL(p.langBaseURL(), p.URLs.URL(p.Path, o.Extension)),
+		BaseURL:     p.BaseURL,
+		LangBaseURL: p.langBaseURL(),
+		Lang:        p.Lang.Code,
+		Alternates:  p.Alternates(o),
+		Translate:   p.Translate,
+		Debug:       p.Debug,
+		TOC:         p.TOC,
+	}, nil
+}
+
+// decorate adds the page path to render errors.
+func (p Page) decorate(err error) error {
+	var renderErr *rendering.RenderError
+	if errors.As(err, &renderErr) {
+		renderErr.Page = p.Path
+	}
+	return err
+}
+
+// URL returns the root-relative URL of an output format including the base path.
+func (p Page) URL(o OutputFormat) string {
+	return urls.RelURL(p.langBaseURL(), p.URLs.URL(p.Path, o.Extension))
+}
+
+// Alternates returns the absolute URLs of the page in every site language.
+func (p Page) Alternates(o OutputFormat) []i18n.Alternate {
+	alternates := make([]i18n.Alternate, 0, len(p.Languages))
+	for _, l := range p.Languages {
+		alternates = append(alternates, i18n.Alternate{
+			Lang: l.Code,
+			URL:  urls.AbsURL(l.BaseURL, p.URLs.URL(p.Path, o.Extension)),
+		})
+	}
+	return alternates
+}
+
+func (p Page) langBaseURL() string {
+	if p.Lang.BaseURL != "" {
+		return p.Lang.BaseURL
+	}
+	return p.BaseURL
+}
+
+func (p Page) resolve(o OutputFormat) (rendering.Renderer, string) {
+	renderer := o.Renderer
+	if renderer == nil {
+		renderer = p.Renderer
+	}
+
+	tmpl := o.Template
+	if tmpl == "" {
+		tmpl = p.Template
 	}
 
-	return p.Renderer.Render(rendering.RenderContext{
-		Data:     p.Data,
-		Template: p.Template,
-	})
+	return renderer, tmpl
 }
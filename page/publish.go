@@ -0,0 +1,82 @@
+package page
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/janmarkuslanger/ssgo/rendering"
+)
+
+const (
+	DraftKey       = "draft"
+	PublishDateKey = "publishDate"
+	ExpiryDateKey  = "expiryDate"
+)
+
+// PublishPolicy decides whether a page is published based on the draft,
+// publishDate and expiryDate keys in its data.
+type PublishPolicy struct {
+	// Now returns the current time. Defaults to time.Now.
+	Now func() time.Time
+	// IncludeUnpublished keeps drafts, scheduled and expired pages.
+	IncludeUnpublished bool
+}
+
+func (p PublishPolicy) now() time.Time {
+	if p.Now == nil {
+		return time.Now()
+	}
+	return p.Now()
+}
+
+// SkipReason returns why the data is not published, or an empty string if it is.
+func (p PublishPolicy) SkipReason(data map[string]any) string {
+	if p.IncludeUnpublished || data == nil {
+		return ""
+	}
+
+	if isTrue(data[DraftKey]) {
+		return "draft"
+	}
+
+	now := p.now()
+	if t, ok := parseDate(data[PublishDateKey]); ok && t.After(now) {
+		return "scheduled for " + t.Format(time.RFC3339)
+	}
+	if t, ok := parseDate(data[ExpiryDateKey]); ok && !t.After(now) {
+		return "expired at " + t.Format(time.RFC3339)
+	}
+
+	return ""
+}
+
+func (p PublishPolicy) IsPublished(data map[string]any) bool {
+	return p.SkipReason(data) == ""
+}
+
+// Filter returns the published entries, e.g. for listings, feeds and sitemaps.
+func (p PublishPolicy) Filter(items []map[string]any) []map[string]any {
+	published := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if p.IsPublished(item) {
+			published = append(published, item)
+		}
+	}
+	return published
+}
+
+func isTrue(v any) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case string:
+		b, _ := strconv.ParseBool(t)
+		return b
+	}
+	return false
+}
+
+func parseDate(v any) (time.Time, bool) {
+	t, err := rendering.ParseDate(v)
+	return t, err == nil && !t.IsZero()
+}
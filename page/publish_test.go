@@ -0,0 +1,83 @@
+package page_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/janmarkuslanger/ssgo/page"
+)
+
+func fixedClock() time.Time {
+	return time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+}
+
+func TestPublishPolicy_SkipReason(t *testing.T) {
+	p := page.PublishPolicy{Now: fixedClock}
+
+	cases := []struct {
+		name string
+		data map[string]any
+		want string
+	}{
+		{name: "nil data", data: nil, want: ""},
+		{name: "published", data: map[string]any{"title": "x"}, want: ""},
+		{name: "draft", data: map[string]any{"draft": true}, want: "draft"},
+		{name: "draft string", data: map[string]any{"draft": "true"}, want: "draft"},
+		{name: "not draft", data: map[string]any{"draft": false}, want: ""},
+		{name: "past publish date", data: map[string]any{"publishDate": "2025-01-01"}, want: ""},
+		{
+			name: "scheduled",
+			data: map[string]any{"publishDate": "2025-07-01"},
+			want: "scheduled for 2025-07-01T00:00:00Z",
+		},
+		{
+			name: "scheduled time value",
+			data: map[string]any{"publishDate": time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+			want: "scheduled for 2026-01-01T00:00:00Z",
+		},
+		{
+			name: "expired",
+			data: map[string]any{"expiryDate": "2025-06-01T12:00:00Z"},
+			want: "expired at 2025-06-01T12:00:00Z",
+		},
+		{name: "not yet expired", data: map[string]any{"expiryDate": "2025-06-02"}, want: ""},
+		{name: "invalid date", data: map[string]any{"publishDate": "tomorrow"}, want: ""},
+	}
+
+	for _, c := range cases {
+		if got := p.SkipReason(c.data); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestPublishPolicy_IncludeUnpublished(t *testing.T) {
+	p := page.PublishPolicy{Now: fixedClock, IncludeUnpublished: true}
+
+	if !p.IsPublished(map[string]any{"draft": true}) {
+		t.Error("drafts should be included")
+	}
+}
+
+func TestPublishPolicy_Filter(t *testing.T) {
+	p := page.PublishPolicy{Now: fixedClock}
+	items := []map[string]any{
+		{"title": "a"},
+		{"title": "b", "draft": true},
+		{"title": "c", "publishDate": "2030-01-01"},
+		{"title": "d", "expiryDate": "2020-01-01"},
+	}
+
+	got := p.Filter(items)
+	if len(got) != 1 || got[0]["title"] != "a" {
+		t.Errorf("unexpected filtered items: %v", got)
+	}
+}
+
+func TestPublishPolicy_DefaultClock(t *testing.T) {
+	p := page.PublishPolicy{}
+
+	if p.IsPublished(map[string]any{"publishDate": time.Now().Add(time.Hour).Format(time.RFC3339)}) {
+		t.Error("future page should not be published")
+	}
+}
@@ -0,0 +1,103 @@
+package redirect
+
+import (
+	"fmt"
+	"html/template"
+	"sort"
+	"strings"
+)
+
+const AliasesKey = "aliases"
+
+type Redirect struct {
+	From string
+	To   string
+}
+
+// File is a server specific redirect configuration written to Path in the output dir.
+type File struct {
+	Path   string
+	Render func(redirects []Redirect) string
+}
+
+var (
+	Netlify = File{Path: "_redirects", Render: netlify}
+	Nginx   = File{Path: "redirects.map", Render: nginx}
+	Apache  = File{Path: ".htaccess", Render: apache}
+)
+
+var pageTemplate = template.Must(template.New("redirect").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<title>{{ . }}</title>
+<link rel="canonical" href="{{ . }}">
+<meta charset="utf-8">
+<meta name="robots" content="noindex">
+<meta http-equiv="refresh" content="0; url={{ . }}">
+</head>
+<body><a href="{{ . }}">{{ . }}</a></body>
+</html>
+`))
+
+// Page returns an HTML page that redirects to the given URL via meta refresh.
+func Page(to string) string {
+	var b strings.Builder
+	pageTemplate.Execute(&b, to)
+	return b.String()
+}
+
+// Aliases returns the alias paths listed under the "aliases" key of page data.
+func Aliases(data map[string]any) []string {
+	switch v := data[AliasesKey].(type) {
+	case []string:
+		return v
+	case []any:
+		aliases := make([]string, 0, len(v))
+		for _, a := range v {
+			if s, ok := a.(string); ok {
+				aliases = append(aliases, s)
+			}
+		}
+		return aliases
+	case string:
+		return []string{v}
+	}
+	return nil
+}
+
+// Sort orders redirects by their source path for reproducible output.
+func Sort(redirects []Redirect) {
+	sort.Slice(redirects, func(i, j int) bool {
+		return redirects[i].From < redirects[j].From
+	})
+}
+
+func netlify(redirects []Redirect) string {
+	var b strings.Builder
+	for _, r := range redirects {
+		fmt.Fprintf(&b, "%s %s 301\n", url(r.From), r.To)
+	}
+	return b.String()
+}
+
+func nginx(redirects []Redirect) string {
+	var b strings.Builder
+	b.WriteString("map $uri $redirect_uri {\n")
+	for _, r := range redirects {
+		fmt.Fprintf(&b, "    %s %s;\n", url(r.From), r.To)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+func apache(redirects []Redirect) string {
+	var b strings.Builder
+	for _, r := range redirects {
+		fmt.Fprintf(&b, "Redirect 301 %s %s\n", url(r.From), r.To)
+	}
+	return b.String()
+}
+
+func url(path string) string {
+	return "/" + strings.TrimPrefix(path, "/")
+}
@@ -0,0 +1,95 @@
+package redirect_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/janmarkuslanger/ssgo/redirect"
+)
+
+func TestPage(t *testing.T) {
+	out := redirect.Page("/blog/new.html")
+
+	for _, want := range []string{
+		`<link rel="canonical" href="/blog/new.html">`,
+		`<meta http-equiv="refresh" content="0; url=/blog/new.html">`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestPage_EscapesURL(t *testing.T) {
+	out := redirect.Page(`/x"><script>`)
+
+	if strings.Contains(out, "<script>") {
+		t.Errorf("url was not escaped:\n%s", out)
+	}
+}
+
+func TestAliases(t *testing.T) {
+	cases := []struct {
+		data map[string]any
+		want []string
+	}{
+		{data: map[string]any{"aliases": []string{"a", "b"}}, want: []string{"a", "b"}},
+		{data: map[string]any{"aliases": []any{"a", 1, "b"}}, want: []string{"a", "b"}},
+		{data: map[string]any{"aliases": "a"}, want: []string{"a"}},
+		{data: map[string]any{}, want: nil},
+	}
+
+	for _, c := range cases {
+		got := redirect.Aliases(c.data)
+		if strings.Join(got, ",") != strings.Join(c.want, ",") {
+			t.Errorf("unexpected aliases: got %v, want %v", got, c.want)
+		}
+	}
+}
+
+func TestFiles(t *testing.T) {
+	redirects := []redirect.Redirect{
+		{From: "old", To: "/new.html"},
+		{From: "/older", To: "https://example.com/"},
+	}
+
+	cases := []struct {
+		file redirect.File
+		path string
+		want string
+	}{
+		{
+			file: redirect.Netlify,
+			path: "_redirects",
+			want: "/old /new.html 301\n/older https://example.com/ 301\n",
+		},
+		{
+			file: redirect.Nginx,
+			path: "redirects.map",
+			want: "map $uri $redirect_uri {\n    /old /new.html;\n    /older https://example.com/;\n}\n",
+		},
+		{
+			file: redirect.Apache,
+			path: ".htaccess",
+			want: "Redirect 301 /old /new.html\nRedirect 301 /older https://example.com/\n",
+		},
+	}
+
+	for _, c := range cases {
+		if c.file.Path != c.path {
+			t.Errorf("unexpected path: got %q, want %q", c.file.Path, c.path)
+		}
+		if got := c.file.Render(redirects); got != c.want {
+			t.Errorf("unexpected %s output:\n%s\nexpected:\n%s", c.path, got, c.want)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	redirects := []redirect.Redirect{{From: "b"}, {From: "a"}}
+	redirect.Sort(redirects)
+
+	if redirects[0].From != "a" {
+		t.Errorf("unexpected order: %v", redirects)
+	}
+}
@@ -0,0 +1,153 @@
+package rendering
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"time"
+)
+
+// templateCache keeps parsed template sets keyed by their file list and the
+// names of the custom funcs. Entries are invalidated when a file's size or
+// modification time changes, so edits are picked up by the dev server.
+type templateCache[T cloner[T]] struct {
+	mu      sync.Mutex
+	entries map[cacheKey]*cacheEntry[T]
+}
+
+// cloner is implemented by both html/template and text/template.
+type cloner[T any] interface {
+	Clone() (T, error)
+}
+
+type cacheKey struct {
+	fsys  any
+	files string
+}
+
+type cacheEntry[T any] struct {
+	once   sync.Once
+	tmpl   T
+	err    error
+	stamps []fileStamp
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+var (
+	htmlCache = newTemplateCache[*template.Template]()
+	textCache = newTemplateCache[*texttemplate.Template]()
+)
+
+func newTemplateCache[T cloner[T]]() *templateCache[T] {
+	return &templateCache[T]{entries: make(map[cacheKey]*cacheEntry[T])}
+}
+
+// get returns a clone of the parsed template set, parsing it at most once per
+// version of the files. The clone can be executed and given new funcs
+// without affecting other goroutines.
+// Templates from an fs.FS are keyed by the FS as well; FS values that cannot
+// be identified are parsed on every call.
+func (c *templateCache[T]) get(fsys fs.FS, files []string, funcs map[string]any, parse func() (T, error)) (T, error) {
+	var zero T
+	stamps, err := stat(fsys, files)
+	if err != nil {
+		return zero, err
+	}
+
+	id, ok := fsID(fsys)
+	if !ok {
+		return parse()
+	}
+	key := cacheKey{fsys: id, files: fileKey(files, funcs)}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if !ok || !sameStamps(entry.stamps, stamps) {
+		entry = &cacheEntry[T]{stamps: stamps}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.tmpl, entry.err = parse()
+	})
+	if entry.err != nil {
+		return zero, entry.err
+	}
+
+	return entry.tmpl.Clone()
+}
+
+func stat(fsys fs.FS, files []string) ([]fileStamp, error) {
+	stamps := make([]fileStamp, len(files))
+	for i, f := range files {
+		info, err := statFile(fsys, f)
+		if err != nil {
+			return nil, err
+		}
+		stamps[i] = fileStamp{modTime: info.ModTime(), size: info.Size()}
+	}
+	return stamps, nil
+}
+
+// stampKey encodes the stamps of files, e.g. for TemplateStamp.
+func stampKey(fsys fs.FS, files []string) (string, error) {
+	stamps, err := stat(fsys, files)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for i, s := range stamps {
+		fmt.Fprintf(&b, "%s\x00%d\x00%d\x00", files[i], s.modTime.UnixNano(), s.size)
+	}
+	return b.String(), nil
+}
+
+func sameStamps(a, b []fileStamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].modTime.Equal(b[i].modTime) || a[i].size != b[i].size {
+			return false
+		}
+	}
+	return true
+}
+
+func fsID(fsys fs.FS) (any, bool) {
+	if fsys == nil {
+		return nil, true
+	}
+
+	t := reflect.TypeOf(fsys)
+	if t.Comparable() {
+		return fsys, true
+	}
+
+	switch t.Kind() {
+	case reflect.Map, reflect.Pointer, reflect.Slice, reflect.Func:
+		return reflect.ValueOf(fsys).Pointer(), true
+	}
+	return nil, false
+}
+
+func fileKey(files []string, funcs map[string]any) string {
+	names := make([]string, 0, len(funcs))
+	for name := range funcs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return strings.Join(files, "\x00") + "\x01" + strings.Join(names, "\x00")
+}
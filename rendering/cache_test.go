@@ -0,0 +1,83 @@
+package rendering_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/janmarkuslanger/ssgo/rendering"
+)
+
+func TestHTMLRenderer_Render_ReloadsChangedTemplates(t *testing.T) {
+	tmp := t.TempDir()
+
+	templatePath := filepath.Join(tmp, "index.html")
+	if err := os.WriteFile(templatePath, []byte(`{{ define "root" }}v1{{ end }}`), 0644); err != nil {
+		t.Fatalf("could not write template: %v", err)
+	}
+
+	renderer := rendering.HTMLRenderer{}
+	out, err := renderer.Render(rendering.RenderContext{Template: templatePath})
+	if err != nil || out != "v1" {
+		t.Fatalf("unexpected first render: %q %v", out, err)
+	}
+
+	if err := os.WriteFile(templatePath, []byte(`{{ define "root" }}v2!{{ end }}`), 0644); err != nil {
+		t.Fatalf("could not write template: %v", err)
+	}
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(templatePath, future, future); err != nil {
+		t.Fatalf("could not touch template: %v", err)
+	}
+
+	out, err = renderer.Render(rendering.RenderContext{Template: templatePath})
+	if err != nil || out != "v2!" {
+		t.Fatalf("expected reloaded template, got %q %v", out, err)
+	}
+}
+
+func TestHTMLRenderer_Render_Concurrent(t *testing.T) {
+	tmp := t.TempDir()
+
+	layoutPath := filepath.Join(tmp, "layout.html")
+	if err := os.WriteFile(layoutPath, []byte(`{{ define "root" }}<p>{{ template "content" . }}</p>{{ end }}`), 0644); err != nil {
+		t.Fatalf("could not write layout: %v", err)
+	}
+	templatePath := filepath.Join(tmp, "index.html")
+	if err := os.WriteFile(templatePath, []byte(`{{ define "content" }}{{ .N }} {{ pageURL }}{{ end }}`), 0644); err != nil {
+		t.Fatalf("could not write template: %v", err)
+	}
+
+	renderer := rendering.HTMLRenderer{Layout: []string{layoutPath}}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			url := fmt.Sprintf("/p/%d", i)
+			out, err := renderer.Render(rendering.RenderContext{
+				Data:     map[string]any{"N": i},
+				Template: templatePath,
+				URL:      url,
+			})
+			if err != nil {
+				errs <- err
+				return
+			}
+			if want := fmt.Sprintf("<p>%d %s</p>", i, url); out != want {
+				errs <- fmt.Errorf("got %q, want %q", out, want)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,90 @@
+package rendering
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html"
+	"html/template"
+	"slices"
+	"strings"
+	"text/template/parse"
+)
+
+// Debug collects what an HTMLRenderer with Debug enabled observed while
+// rendering a page. The builder sets RenderContext.Debug per page.
+type Debug struct {
+	// Templates lists the executed templates and blocks in order of first use.
+	Templates []string
+	// Warnings are missing map keys, found by walking the templates against
+	// the data without executing them again.
+	Warnings []*RenderError
+}
+
+// debugFuncs returns the dump function, which prints nothing unless Debug
+// is enabled.
+func (r HTMLRenderer) debugFuncs(ctx RenderContext) template.FuncMap {
+	return template.FuncMap{
+		"dump": func(values ...any) template.HTML {
+			if !r.Debug {
+				return ""
+			}
+
+			var v any = values
+			switch len(values) {
+			case 0:
+				v = map[string]any{
+					"Template": ctx.Template,
+					"URL":      ctx.URL,
+					"Lang":     ctx.Lang,
+					"Data":     ctx.Data,
+					"Site":     ctx.Site,
+				}
+			case 1:
+				v = values[0]
+			}
+			return template.HTML(`<pre class="ssgo-dump">` + html.EscapeString(dump(v)) + `</pre>`)
+		},
+	}
+}
+
+// dump formats v as indented JSON, or with %#v if it cannot be encoded.
+func dump(v any) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Sprintf("%#v", v)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// trace records every template of tmpl in debug.Templates when it is
+// executed. tmpl must be a fresh clone, as its trees are changed in place.
+// The injected {{ if debugTrace "name" }}{{ end }} prints nothing, so the
+// output stays the same in every escaping context, e.g. inside a script.
+func trace(tmpl *template.Template, debug *Debug) error {
+	tmpl.Funcs(template.FuncMap{
+		"debugTrace": func(name string) bool {
+			if !slices.Contains(debug.Templates, name) {
+				debug.Templates = append(debug.Templates, name)
+			}
+			return false
+		},
+	})
+
+	for _, t := range tmpl.Templates() {
+		if t.Tree == nil || t.Tree.Root == nil || parse.IsEmptyTree(t.Tree.Root) {
+			continue
+		}
+
+		// Parse only checks that a function of that name exists.
+		trees, err := parse.Parse("trace", fmt.Sprintf("{{ if debugTrace %q }}{{ end }}", t.Name()), "", "", map[string]any{"debugTrace": fmt.Sprint})
+		if err != nil {
+			return err
+		}
+		t.Tree.Root.Nodes = append([]parse.Node{trees["trace"].Root.Nodes[0]}, t.Tree.Root.Nodes...)
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package rendering_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/janmarkuslanger/ssgo/rendering"
+)
+
+var debugFS = fstest.MapFS{
+	"layout.html": {Data: []byte(`{{ define "root" }}{{ block "title" . }}T{{ end }}|{{ template "content" . }}{{ end }}`)},
+	"page.html":   {Data: []byte("{{ define \"content\" }}{{ template \"card\" . }}\n{{ .missing }}{{ end }}\n{{ define \"card\" }}<b>{{ .title }}</b>{{ end }}")},
+	"dump.html":   {Data: []byte(`{{ define "content" }}{{ dump .title }}{{ end }}`)},
+}
+
+func TestHTMLRenderer_Debug(t *testing.T) {
+	r := rendering.HTMLRenderer{FS: debugFS, Layout: []string{"layout.html"}, Debug: true}
+	debug := &rendering.Debug{}
+
+	out, err := r.Render(rendering.RenderContext{Template: "page.html", Data: map[string]any{"title": "<Hi>"}, Debug: debug})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if out != "T|<b>&lt;Hi&gt;</b>\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	if got := strings.Join(debug.Templates, ","); got != "root,title,content,card" {
+		t.Errorf("unexpected templates: %s", got)
+	}
+	if len(debug.Warnings) != 1 {
+		t.Fatalf("expected one warning, got %v", debug.Warnings)
+	}
+	w := debug.Warnings[0]
+	if w.Template != "page.html" || w.Line != 2 || !strings.Contains(w.Message, `map has no entry for key "missing"`) {
+		t.Errorf("unexpected warning: %+v", w)
+	}
+}
+
+func TestHTMLRenderer_Debug_ScriptContext(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`{{ define "root" }}<script>var n = {{ template "content" . }};</script>{{ end }}`)},
+		"page.html":   {Data: []byte(`{{ define "content" }} {{ .n }} {{ end }}`)},
+	}
+	ctx := rendering.RenderContext{Template: "page.html", Data: map[string]any{"n": 1}}
+
+	plain, err := rendering.HTMLRenderer{FS: fsys, Layout: []string{"layout.html"}}.Render(ctx)
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	ctx.Debug = &rendering.Debug{}
+	debugged, err := rendering.HTMLRenderer{FS: fsys, Layout: []string{"layout.html"}, Debug: true}.Render(ctx)
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	if !strings.Contains(plain, " 1 ") || debugged != plain {
+		t.Errorf("debug mode changed the output:\n%q\n%q", plain, debugged)
+	}
+	if got := strings.Join(ctx.Debug.Templates, ","); got != "root,content" {
+		t.Errorf("unexpected templates: %s", got)
+	}
+}
+
+func TestHTMLRenderer_Debug_Dump(t *testing.T) {
+	ctx := rendering.RenderContext{Template: "dump.html", Data: map[string]any{"title": map[string]any{"a": "<b>"}}}
+
+	out, err := rendering.HTMLRenderer{FS: debugFS, Layout: []string{"layout.html"}, Debug: true}.Render(ctx)
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	want := "T|<pre class=\"ssgo-dump\">{\n  &#34;a&#34;: &#34;&lt;b&gt;&#34;\n}</pre>"
+	if out != want {
+		t.Errorf("got  %q\nwant %q", out, want)
+	}
+
+	// Without Debug, dump calls render nothing.
+	out, err = rendering.HTMLRenderer{FS: debugFS, Layout: []string{"layout.html"}}.Render(ctx)
+	if err != nil || out != "T|" {
+		t.Errorf("unexpected output: %q, %v", out, err)
+	}
+}
+
+func TestHTMLRenderer_Debug_MissingKeys(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html": {Data: []byte(`{{ define "root" }}{{ .a }}{{ count }}
+{{ range .posts }}{{ template "card" . }}{{ end }}
+{{ with .author }}{{ .name }}{{ .email }}{{ end }}{{ if .draft }}{{ .never }}{{ end }}
+{{ $p := .post }}{{ $p.title }}{{ (index .posts 0).tag }}{{ end }}
+{{ define "card" }}{{ .title }}{{ .summary }}{{ end }}`)},
+	}
+	calls := 0
+	r := rendering.HTMLRenderer{FS: fsys, Debug: true, CustomFuncs: map[string]any{"count": func() string { calls++; return "" }}}
+	data := map[string]any{
+		"posts":  []map[string]any{{"title": "A", "summary": "S"}, {"title": "B"}},
+		"author": map[string]any{"name": "Jan"},
+		"draft":  false,
+		"post":   map[string]any{},
+	}
+
+	debug := &rendering.Debug{}
+	if _, err := r.Render(rendering.RenderContext{Template: "page.html", Data: data, Debug: debug}); err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("the page should be executed once, got %d calls", calls)
+	}
+
+	var got []string
+	for _, w := range debug.Warnings {
+		got = append(got, fmt.Sprintf("%d:%s", w.Line, w.Message))
+	}
+	want := []string{
+		`1:executing "root" at <.a>: map has no entry for key "a"`,
+		`5:executing "card" at <.summary>: map has no entry for key "summary"`,
+		`3:executing "root" at <.email>: map has no entry for key "email"`,
+		`4:executing "root" at <$p.title>: map has no entry for key "title"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got warnings\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestHTMLRenderer_Debug_EscapedTemplateName(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html": {Data: []byte(`{{ define "root" }}<script>var a = {{ template "value" . }};</script>{{ end }}{{ define "value" }}{{ index .list 5 }}{{ end }}`)},
+	}
+
+	_, err := rendering.HTMLRenderer{FS: fsys}.Render(rendering.RenderContext{Template: "page.html", Data: map[string]any{"list": []int{1}}})
+	var re *rendering.RenderError
+	if !errors.As(err, &re) {
+		t.Fatalf("expected a RenderError, got %v", err)
+	}
+	if strings.Contains(re.Message, "$htmltemplate") || !strings.Contains(re.Message, `executing "value"`) {
+		t.Errorf("unexpected message: %s", re.Message)
+	}
+}
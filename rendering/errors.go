@@ -0,0 +1,163 @@
+package rendering
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// excerptContext is the number of lines shown before and after the failing line.
+const excerptContext = 2
+
+var templateErrorPattern = regexp.MustCompile(`(?s)(?:html/)?template: ?([^:\s]+):(\d+)(?::(\d+))?: (.*)$`)
+
+// escapedNamePattern matches the suffix html/template adds to the names of
+// templates it escapes for another context, e.g. card$htmltemplate_stateJS.
+var escapedNamePattern = regexp.MustCompile(`\$htmltemplate_\w*`)
+
+// RenderError describes a failed render with the template location and the
+// surrounding source lines.
+type RenderError struct {
+	// Page is the path of the page being rendered, set by page.Page.
+	Page     string
+	Template string
+	Line     int
+	// Column is 1-based; 0 if unknown.
+	Column  int
+	Message string
+	Excerpt []SourceLine
+	Hint    string
+	Err     error
+}
+
+type SourceLine struct {
+	Number int
+	Text   string
+}
+
+func (e *RenderError) Error() string {
+	loc := e.Template
+	if e.Line > 0 {
+		loc += ":" + strconv.Itoa(e.Line)
+		if e.Column > 0 {
+			loc += ":" + strconv.Itoa(e.Column)
+		}
+	}
+	if loc == "" {
+		return e.Message
+	}
+	return loc + ": " + e.Message
+}
+
+func (e *RenderError) Unwrap() error {
+	return e.Err
+}
+
+// Detail returns the hint and the source excerpt with the failing line
+// marked, e.g. for terminal output.
+func (e *RenderError) Detail() string {
+	var b strings.Builder
+	if e.Hint != "" {
+		b.WriteString("hint: " + e.Hint + "\n")
+	}
+
+	width := len(strconv.Itoa(e.Line + excerptContext))
+	for _, l := range e.Excerpt {
+		marker := "  "
+		if l.Number == e.Line {
+			marker = "> "
+		}
+		fmt.Fprintf(&b, "%s%*d | %s\n", marker, width, l.Number, l.Text)
+		if l.Number == e.Line && e.Column > 0 {
+			fmt.Fprintf(&b, "  %*s | %s^\n", width, "", strings.Repeat(" ", e.Column-1))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// newRenderError maps a template error to the file it came from. files are
+// the parsed files in order; template errors only carry the base name, so
+// the last file with a matching base name wins.
+func newRenderError(fsys fs.FS, files []string, err error) error {
+	var re *RenderError
+	if errors.As(err, &re) {
+		return err
+	}
+
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) && errors.Is(err, fs.ErrNotExist) {
+		return &RenderError{
+			Template: pathErr.Path,
+			Message:  err.Error(),
+			Hint:     "the template file does not exist",
+			Err:      err,
+		}
+	}
+
+	m := templateErrorPattern.FindStringSubmatch(err.Error())
+	if m == nil {
+		return &RenderError{Message: err.Error(), Hint: hint(err.Error()), Err: err}
+	}
+
+	re = &RenderError{Template: m[1], Message: escapedNamePattern.ReplaceAllString(m[4], ""), Err: err}
+	re.Line, _ = strconv.Atoi(m[2])
+	if m[3] != "" {
+		col, _ := strconv.Atoi(m[3])
+		re.Column = col + 1
+	}
+	re.Hint = hint(re.Message)
+
+	for _, f := range files {
+		if path.Base(filepath.ToSlash(f)) == m[1] {
+			re.Template = f
+		}
+	}
+	if content, err := readFile(fsys, re.Template); err == nil {
+		re.Excerpt = sourceExcerpt(string(content), re.Line)
+	}
+
+	return re
+}
+
+func sourceExcerpt(content string, line int) []SourceLine {
+	lines := strings.Split(content, "\n")
+	if line < 1 || line > len(lines) {
+		return nil
+	}
+
+	var out []SourceLine
+	for n := max(1, line-excerptContext); n <= min(len(lines), line+excerptContext); n++ {
+		out = append(out, SourceLine{Number: n, Text: strings.TrimRight(lines[n-1], "\r")})
+	}
+	return out
+}
+
+var (
+	missingFieldPattern    = regexp.MustCompile(`can't evaluate field (\w+)|map has no entry for key "([^"]+)"`)
+	missingFuncPattern     = regexp.MustCompile(`function "([^"]+)" not defined`)
+	missingTemplatePattern = regexp.MustCompile(`no such template "([^"]+)"|template "([^"]+)" not defined`)
+)
+
+func hint(msg string) string {
+	if m := missingFieldPattern.FindStringSubmatch(msg); m != nil {
+		return fmt.Sprintf("missing key .%s in data", m[1]+m[2])
+	}
+	if m := missingFuncPattern.FindStringSubmatch(msg); m != nil {
+		return fmt.Sprintf("function %s is neither built in nor in CustomFuncs", m[1])
+	}
+	if strings.Contains(msg, "incomplete or empty template") {
+		return `layouts must define "root" and content templates "content"`
+	}
+	if m := missingTemplatePattern.FindStringSubmatch(msg); m != nil {
+		return fmt.Sprintf("template %q is not defined by the layouts, partials or page template", m[1]+m[2])
+	}
+	if strings.Contains(msg, "nil pointer evaluating") {
+		return "a value in the data is nil; guard it with with or if"
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package rendering_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/janmarkuslanger/ssgo/rendering"
+)
+
+func renderError(t *testing.T, fsys fstest.MapFS, ctx rendering.RenderContext) *rendering.RenderError {
+	t.Helper()
+	r := rendering.HTMLRenderer{FS: fsys, Layout: []string{"layouts/base.html"}}
+
+	_, err := r.Render(ctx)
+	var renderErr *rendering.RenderError
+	if !errors.As(err, &renderErr) {
+		t.Fatalf("expected RenderError, got %T: %v", err, err)
+	}
+	return renderErr
+}
+
+func TestRenderError_ExecError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layouts/base.html": {Data: []byte(`{{ define "root" }}{{ template "content" . }}{{ end }}`)},
+		"pages/post.html":   {Data: []byte("{{ define \"content\" }}\n<article>\n  <h1>{{ .Title.Text }}</h1>\n</article>\n{{ end }}")},
+	}
+
+	e := renderError(t, fsys, rendering.RenderContext{Template: "pages/post.html", Data: map[string]any{"Title": "x"}})
+
+	if e.Template != "pages/post.html" || e.Line != 3 || e.Column != 16 {
+		t.Errorf("unexpected location %s:%d:%d", e.Template, e.Line, e.Column)
+	}
+	if e.Hint != "missing key .Text in data" {
+		t.Errorf("unexpected hint: %q", e.Hint)
+	}
+	if len(e.Excerpt) != 5 || e.Excerpt[0].Number != 1 || e.Excerpt[2].Text != "  <h1>{{ .Title.Text }}</h1>" {
+		t.Errorf("unexpected excerpt: %v", e.Excerpt)
+	}
+	if !strings.HasPrefix(e.Error(), "pages/post.html:3:16: executing") {
+		t.Errorf("unexpected message: %s", e.Error())
+	}
+
+	detail := e.Detail()
+	if !strings.Contains(detail, "> 3 |   <h1>{{ .Title.Text }}</h1>\n    |                ^") {
+		t.Errorf("unexpected detail:\n%s", detail)
+	}
+}
+
+func TestRenderError_ParseError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layouts/base.html": {Data: []byte("{{ define \"root\" }}\n{{ nope }}\n{{ end }}")},
+		"pages/post.html":   {Data: []byte(`{{ define "content" }}{{ end }}`)},
+	}
+
+	e := renderError(t, fsys, rendering.RenderContext{Template: "pages/post.html"})
+
+	if e.Template != "layouts/base.html" || e.Line != 2 || e.Column != 0 {
+		t.Errorf("unexpected location %s:%d:%d", e.Template, e.Line, e.Column)
+	}
+	if !strings.Contains(e.Hint, "CustomFuncs") {
+		t.Errorf("unexpected hint: %q", e.Hint)
+	}
+}
+
+func TestRenderError_MissingTemplate(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layouts/base.html": {Data: []byte(`{{ define "root" }}{{ end }}`)},
+	}
+
+	e := renderError(t, fsys, rendering.RenderContext{Template: "pages/missing.html"})
+
+	if e.Template != "pages/missing.html" || e.Hint != "the template file does not exist" {
+		t.Errorf("unexpected error: %+v", e)
+	}
+}
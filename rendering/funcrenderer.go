@@ -0,0 +1,80 @@
+package rendering
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+)
+
+// Component writes the content of a page in Go.
+type Component func(ctx RenderContext, w io.Writer) error
+
+// FuncRenderer renders pages with Components instead of template files. The
+// page's Template is the name of its component.
+type FuncRenderer struct {
+	Components map[string]Component
+	// Layout wraps the component output when set: its layouts are executed
+	// with the output as the "content" template.
+	Layout *HTMLRenderer
+}
+
+func (r FuncRenderer) Render(ctx RenderContext) (string, error) {
+	var buf bytes.Buffer
+	if err := r.RenderTo(ctx, &buf); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// RenderTo writes the component output directly into w unless it is
+// wrapped in a layout.
+func (r FuncRenderer) RenderTo(ctx RenderContext, w io.Writer) error {
+	component, ok := r.Components[ctx.Template]
+	if !ok {
+		return fmt.Errorf("component %q is not registered", ctx.Template)
+	}
+
+	if r.Layout == nil || len(r.Layout.Layout) == 0 {
+		if err := component(ctx, w); err != nil {
+			return fmt.Errorf("component %q: %w", ctx.Template, err)
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := component(ctx, &buf); err != nil {
+		return fmt.Errorf("component %q: %w", ctx.Template, err)
+	}
+
+	return r.Layout.renderComponent(ctx, template.HTML(buf.String()), w)
+}
+
+func (r FuncRenderer) HasTemplate(name string) bool {
+	_, ok := r.Components[name]
+	return ok
+}
+
+// TemplateStamp changes whenever the layouts change. Components are Go code
+// and have no stamp.
+func (r FuncRenderer) TemplateStamp(name string) (string, error) {
+	if r.Layout == nil || len(r.Layout.Layout) == 0 {
+		return "", nil
+	}
+	return r.Layout.TemplateStamp("")
+}
+
+func (r FuncRenderer) ValidateTemplates(templates []string) []error {
+	var errs []error
+	for _, name := range templates {
+		if name != "" && !r.HasTemplate(name) {
+			errs = append(errs, fmt.Errorf("component %q is not registered", name))
+		}
+	}
+
+	if r.Layout != nil {
+		errs = append(errs, r.Layout.ValidateTemplates(nil)...)
+	}
+	return errs
+}
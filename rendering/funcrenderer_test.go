@@ -0,0 +1,77 @@
+package rendering_test
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/janmarkuslanger/ssgo/rendering"
+	"github.com/janmarkuslanger/ssgo/toc"
+)
+
+var components = map[string]rendering.Component{
+	"hello": func(ctx rendering.RenderContext, w io.Writer) error {
+		_, err := fmt.Fprintf(w, "<h2>Hello %s</h2>", html.EscapeString(fmt.Sprint(ctx.Data["name"])))
+		return err
+	},
+	"fail": func(ctx rendering.RenderContext, w io.Writer) error {
+		return errors.New("boom")
+	},
+}
+
+func TestFuncRenderer_Render(t *testing.T) {
+	r := rendering.FuncRenderer{Components: components}
+
+	out, err := r.Render(rendering.RenderContext{Template: "hello", Data: map[string]any{"name": "<Go>"}})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if out != "<h2>Hello &lt;Go&gt;</h2>" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestFuncRenderer_Render_Layout(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`{{ define "root" }}<title>{{ .title }}</title><nav>{{ .TOC.HTML }}</nav>{{ template "content" . }}{{ end }}`)},
+	}
+	r := rendering.FuncRenderer{
+		Components: components,
+		Layout:     &rendering.HTMLRenderer{FS: fsys, Layout: []string{"layout.html"}, Headings: &toc.Options{}},
+	}
+
+	out, err := r.Render(rendering.RenderContext{Template: "hello", Data: map[string]any{"title": "T", "name": "Go"}})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	want := `<title>T</title><nav><ul><li><a href="#hello-go">Hello Go</a></li></ul></nav><h2 id="hello-go">Hello Go</h2>`
+	if out != want {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestFuncRenderer_Errors(t *testing.T) {
+	r := rendering.FuncRenderer{Components: components}
+
+	if _, err := r.Render(rendering.RenderContext{Template: "missing"}); err == nil || !strings.Contains(err.Error(), `component "missing" is not registered`) {
+		t.Errorf("expected missing component error, got %v", err)
+	}
+	if _, err := r.Render(rendering.RenderContext{Template: "fail"}); err == nil || err.Error() != `component "fail": boom` {
+		t.Errorf("expected component error, got %v", err)
+	}
+
+	if !r.HasTemplate("hello") || r.HasTemplate("missing") {
+		t.Error("unexpected HasTemplate result")
+	}
+
+	r.Layout = &rendering.HTMLRenderer{FS: fstest.MapFS{"layout.html": {Data: []byte(`<p>no root</p>`)}}, Layout: []string{"layout.html"}}
+	errs := r.ValidateTemplates([]string{"hello", "missing"})
+	if len(errs) != 2 || !strings.Contains(errs[0].Error(), "missing") || !strings.Contains(errs[1].Error(), `do not define "root"`) {
+		t.Errorf("unexpected validation errors: %v", errs)
+	}
+}
@@ -0,0 +1,485 @@
+package rendering
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/janmarkuslanger/ssgo/highlight"
+	"github.com/janmarkuslanger/ssgo/summary"
+	"github.com/janmarkuslanger/ssgo/toc"
+	"github.com/janmarkuslanger/ssgo/urls"
+	"github.com/yuin/goldmark"
+)
+
+// DateLayouts are the layouts ParseDate accepts for date strings.
+var DateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+var tagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// Group is one entry of the groupBy template function.
+type Group struct {
+	Key   any
+	Items []any
+}
+
+// DefaultFuncs returns the template functions available in every
+// HTMLRenderer template. CustomFuncs with the same name take precedence.
+// The list constructor is not called slice to keep the builtin intact.
+func DefaultFuncs() template.FuncMap {
+	return template.FuncMap{
+		"now":         time.Now,
+		"parseDate":   ParseDate,
+		"dateFormat":  dateFormat,
+		"slugify":     urls.Slugify,
+		"truncate":    truncate,
+		"excerpt":     excerpt,
+		"markdownify": markdownify,
+		"highlight":   func(lang string, code any) template.HTML { return highlight.Block(toString(code), lang) },
+		"toc":         func(v any) toc.TOC { return toc.Extract(toString(v), toc.Options{}) },
+		"summary":     func(n int, v any) template.HTML { return summary.New(toString(v), summary.Options{Words: n}).HTML },
+		"wordCount":   func(v any) int { return summary.CountWords(toString(v)) },
+		"readingTime": func(v any) int { return summary.ReadingTime(summary.CountWords(toString(v)), 0) },
+		"safeHTML":    func(v any) template.HTML { return template.HTML(toString(v)) },
+		"safeURL":     func(v any) template.URL { return template.URL(toString(v)) },
+		"dict":        dict,
+		"list":        func(items ...any) []any { return items },
+		"first":       first,
+		"where":       where,
+		"sort":        sortBy,
+		"groupBy":     groupBy,
+		"default":     defaultValue,
+		"jsonify":     jsonify,
+		"add":         func(a, b any) (any, error) { return arith(a, b, '+') },
+		"sub":         func(a, b any) (any, error) { return arith(a, b, '-') },
+		"mul":         func(a, b any) (any, error) { return arith(a, b, '*') },
+		"div":         func(a, b any) (any, error) { return arith(a, b, '/') },
+		"mod":         func(a, b any) (any, error) { return arith(a, b, '%') },
+		"round":       round,
+	}
+}
+
+// ParseDate returns a time.Time as it is and parses strings with
+// DateLayouts. It backs the parseDate template function and the publish
+// dates of pages.
+func ParseDate(v any) (time.Time, error) {
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		for _, layout := range DateLayouts {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed, nil
+			}
+		}
+		return time.Time{}, fmt.Errorf("unsupported date %q", t)
+	}
+	return time.Time{}, fmt.Errorf("unsupported date type %T", v)
+}
+
+// dateFormat formats a time.Time or date string with a Go layout. Empty
+// values render as an empty string.
+func dateFormat(layout string, v any) (string, error) {
+	if v == nil || v == "" {
+		return "", nil
+	}
+	t, err := ParseDate(v)
+	if err != nil {
+		return "", err
+	}
+	return t.Format(layout), nil
+}
+
+// truncate shortens s to at most n runes, cutting at the last word boundary
+// and appending an ellipsis.
+func truncate(n int, v any) string {
+	s := toString(v)
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+
+	cut := string(runes[:n])
+	if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > 0 && !unicode.IsSpace(runes[n]) {
+		cut = cut[:i]
+	}
+	return strings.TrimRightFunc(cut, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
+
+// excerpt strips HTML tags from s and truncates the plain text to n runes.
+func excerpt(n int, v any) string {
+	text := tagPattern.ReplaceAllString(toString(v), " ")
+	return truncate(n, strings.Join(strings.Fields(text), " "))
+}
+
+func markdownify(v any) (template.HTML, error) {
+	var buf bytes.Buffer
+	if err := goldmark.Convert([]byte(toString(v)), &buf); err != nil {
+		return "", err
+	}
+	return template.HTML(buf.String()), nil
+}
+
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict expects key/value pairs, got %d arguments", len(pairs))
+	}
+
+	m := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key must be a string, got %T", pairs[i])
+		}
+		m[key] = pairs[i+1]
+	}
+	return m, nil
+}
+
+func defaultValue(def any, v any) any {
+	if isEmpty(v) {
+		return def
+	}
+	return v
+}
+
+func jsonify(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func first(n int, coll any) ([]any, error) {
+	items, err := toList(coll)
+	if err != nil {
+		return nil, err
+	}
+	if n < len(items) {
+		items = items[:n]
+	}
+	return items, nil
+}
+
+// where filters a collection by a key, e.g. where .posts "draft" false or
+// where .posts "weight" ">" 10. Keys may be dotted paths.
+func where(coll any, key string, args ...any) ([]any, error) {
+	op, want := "==", any(nil)
+	switch len(args) {
+	case 1:
+		want = args[0]
+	case 2:
+		o, ok := args[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("where operator must be a string, got %T", args[0])
+		}
+		op, want = o, args[1]
+	default:
+		return nil, fmt.Errorf("where expects a value or an operator and a value")
+	}
+
+	items, err := toList(coll)
+	if err != nil {
+		return nil, err
+	}
+
+	var matched []any
+	for _, item := range items {
+		ok, err := compareOp(lookup(item, key), op, want)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, item)
+		}
+	}
+	return matched, nil
+}
+
+// sortBy sorts a collection by a key, or by its values if the key is empty.
+// An optional "desc" argument reverses the order.
+func sortBy(coll any, args ...string) ([]any, error) {
+	key, order := "", "asc"
+	if len(args) > 0 {
+		key = args[0]
+	}
+	if len(args) > 1 {
+		order = args[1]
+	}
+	if order != "asc" && order != "desc" {
+		return nil, fmt.Errorf("sort order must be asc or desc, got %q", order)
+	}
+
+	items, err := toList(coll)
+	if err != nil {
+		return nil, err
+	}
+
+	sorted := make([]any, len(items))
+	copy(sorted, items)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := lookup(sorted[i], key), lookup(sorted[j], key)
+		if order == "desc" {
+			return compare(b, a) < 0
+		}
+		return compare(a, b) < 0
+	})
+	return sorted, nil
+}
+
+// groupBy groups a collection by a key, keeping the order in which keys
+// first appear.
+func groupBy(coll any, key string) ([]Group, error) {
+	items, err := toList(coll)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []Group
+	index := make(map[string]int)
+	for _, item := range items {
+		k := lookup(item, key)
+		id := fmt.Sprint(k)
+		i, ok := index[id]
+		if !ok {
+			i = len(groups)
+			index[id] = i
+			groups = append(groups, Group{Key: k})
+		}
+		groups[i].Items = append(groups[i].Items, item)
+	}
+	return groups, nil
+}
+
+// toString accepts strings and string types such as template.HTML, so
+// functions can be chained after markdownify or shortcodes.
+func toString(v any) string {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.String {
+		return rv.String()
+	}
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+func toList(coll any) ([]any, error) {
+	if coll == nil {
+		return nil, nil
+	}
+
+	v := reflect.ValueOf(coll)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected a list, got %T", coll)
+	}
+
+	items := make([]any, v.Len())
+	for i := range items {
+		items[i] = v.Index(i).Interface()
+	}
+	return items, nil
+}
+
+// lookup resolves a dotted key in maps and structs. An empty key returns
+// the item itself.
+func lookup(item any, key string) any {
+	if key == "" {
+		return item
+	}
+
+	v := reflect.ValueOf(item)
+	for _, part := range strings.Split(key, ".") {
+		for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+
+		switch v.Kind() {
+		case reflect.Map:
+			if v.Type().Key().Kind() != reflect.String {
+				return nil
+			}
+			v = v.MapIndex(reflect.ValueOf(part).Convert(v.Type().Key()))
+		case reflect.Struct:
+			v = v.FieldByName(part)
+		default:
+			return nil
+		}
+		if !v.IsValid() {
+			return nil
+		}
+	}
+	return v.Interface()
+}
+
+func compareOp(a any, op string, b any) (bool, error) {
+	switch op {
+	case "=", "==", "eq":
+		return compare(a, b) == 0, nil
+	case "!=", "ne":
+		return compare(a, b) != 0, nil
+	case "<", "lt":
+		return compare(a, b) < 0, nil
+	case "<=", "le":
+		return compare(a, b) <= 0, nil
+	case ">", "gt":
+		return compare(a, b) > 0, nil
+	case ">=", "ge":
+		return compare(a, b) >= 0, nil
+	}
+	return false, fmt.Errorf("unknown where operator %q", op)
+}
+
+// compare orders numbers numerically, times chronologically and everything
+// else by its string form.
+func compare(a, b any) int {
+	if fa, err := toFloat(a); err == nil {
+		if fb, err := toFloat(b); err == nil {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+
+	if ta, ok := a.(time.Time); ok {
+		if tb, ok := b.(time.Time); ok {
+			return ta.Compare(tb)
+		}
+	}
+
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0
+		case a == nil:
+			return -1
+		}
+		return 1
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+func isEmpty(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	}
+	return rv.IsZero()
+}
+
+func toFloat(v any) (float64, error) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+	return 0, fmt.Errorf("expected a number, got %T", v)
+}
+
+func toInt(v any) (int64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(rv.Uint()), true
+	}
+	return 0, false
+}
+
+func round(v any) (float64, error) {
+	f, err := toFloat(v)
+	if err != nil {
+		return 0, err
+	}
+	return math.Round(f), nil
+}
+
+// arith keeps integer results for integer operands and falls back to
+// float64 otherwise.
+func arith(a, b any, op rune) (any, error) {
+	ia, aInt := toInt(a)
+	ib, bInt := toInt(b)
+	if aInt && bInt {
+		switch op {
+		case '+':
+			return ia + ib, nil
+		case '-':
+			return ia - ib, nil
+		case '*':
+			return ia * ib, nil
+		case '/', '%':
+			if ib == 0 {
+				return nil, fmt.Errorf("division by zero")
+			}
+			if op == '/' {
+				return ia / ib, nil
+			}
+			return ia % ib, nil
+		}
+	}
+
+	fa, err := toFloat(a)
+	if err != nil {
+		return nil, err
+	}
+	fb, err := toFloat(b)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op {
+	case '+':
+		return fa + fb, nil
+	case '-':
+		return fa - fb, nil
+	case '*':
+		return fa * fb, nil
+	case '/':
+		if fb == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return fa / fb, nil
+	case '%':
+		if fb == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		return math.Mod(fa, fb), nil
+	}
+	return nil, fmt.Errorf("unknown operator %q", op)
+}
@@ -0,0 +1,128 @@
+package rendering_test
+
+import (
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/janmarkuslanger/ssgo/rendering"
+)
+
+func execFuncs(t *testing.T, text string, data any) (string, error) {
+	t.Helper()
+	tmpl, err := template.New("t").Funcs(rendering.DefaultFuncs()).Parse(text)
+	if err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+
+	var b strings.Builder
+	err = tmpl.Execute(&b, data)
+	return b.String(), err
+}
+
+func TestDefaultFuncs(t *testing.T) {
+	posts := []map[string]any{
+		{"title": "B", "weight": 2, "tag": "go", "author": map[string]any{"name": "Jan"}},
+		{"title": "A", "weight": 3, "tag": "web", "author": map[string]any{"name": "Eva"}},
+		{"title": "C", "weight": 1, "tag": "go", "draft": true},
+	}
+	data := map[string]any{
+		"posts": posts,
+		"date":  time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+		"empty": "",
+		"url":   template.URL("javascript:void"),
+		"count": 3,
+	}
+
+	cases := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "dateFormat time", text: `{{ .date | dateFormat "Jan 2, 2006" }}`, want: "Mar 4, 2025"},
+		{name: "dateFormat string", text: `{{ dateFormat "02.01.2006" "2025-12-24" }}`, want: "24.12.2025"},
+		{name: "parseDate", text: `{{ (parseDate "2025-01-02T10:00:00Z").Year }}`, want: "2025"},
+		{name: "slugify", text: `{{ slugify "Hello, World! Über Go" }}`, want: "hello-world-über-go"},
+		{name: "truncate", text: `{{ truncate 12 "The quick brown fox" }}`, want: "The quick…"},
+		{name: "truncate short", text: `{{ truncate 50 "short" }}`, want: "short"},
+		{name: "excerpt", text: `{{ excerpt 20 "<p>Hello <b>big</b> world, how are you</p>" }}`, want: "Hello big world, how…"},
+		{name: "markdownify", text: `{{ markdownify "**bold**" }}`, want: "<p><strong>bold</strong></p>\n"},
+		{name: "excerpt of html", text: `{{ markdownify "Some **bold** text" | excerpt 9 }}`, want: "Some bold…"},
+		{name: "highlight", text: `{{ "x := 1" | highlight "go" }}`, want: `<pre class="hl"><code class="language-go">x := <span class="hl-number">1</span></code></pre>`},
+		{name: "toc", text: `{{ (toc "<h2>A</h2><h3>B</h3>").HTML }}`, want: `<ul><li><a href="#a">A</a><ul><li><a href="#b">B</a></li></ul></li></ul>`},
+		{name: "summary", text: `{{ summary 2 "<p>one <b>two three</b></p>" }}`, want: "<p>one <b>two</b></p>"},
+		{name: "wordCount", text: `{{ wordCount "<p>one <b>two</b></p>" }} {{ readingTime "one" }}`, want: "2 1"},
+		{name: "safeHTML", text: `{{ safeHTML "<em>x</em>" }}`, want: "<em>x</em>"},
+		{name: "safeURL", text: `<a href="{{ safeURL "javascript:void" }}">`, want: `<a href="javascript:void">`},
+		{name: "safeHTML of markdownify", text: `{{ markdownify "*x*" | safeHTML }}`, want: "<p><em>x</em></p>\n"},
+		{name: "safeURL of data", text: `<a href="{{ safeURL .url }}">`, want: `<a href="javascript:void">`},
+		{name: "safeHTML of number", text: `{{ safeHTML .count }}`, want: "3"},
+		{name: "dict", text: `{{ with dict "a" 1 "b" "x" }}{{ .a }}{{ .b }}{{ end }}`, want: "1x"},
+		{name: "list", text: `{{ range list 1 2 3 }}{{ . }}{{ end }}`, want: "123"},
+		{name: "first", text: `{{ range first 2 .posts }}{{ .title }}{{ end }}`, want: "BA"},
+		{name: "where", text: `{{ range where .posts "tag" "go" }}{{ .title }}{{ end }}`, want: "BC"},
+		{name: "where op", text: `{{ range where .posts "weight" ">=" 2 }}{{ .title }}{{ end }}`, want: "BA"},
+		{name: "where nested", text: `{{ range where .posts "author.name" "Eva" }}{{ .title }}{{ end }}`, want: "A"},
+		{name: "sort", text: `{{ range sort .posts "weight" }}{{ .title }}{{ end }}`, want: "CBA"},
+		{name: "sort desc", text: `{{ range sort .posts "title" "desc" }}{{ .title }}{{ end }}`, want: "CBA"},
+		{name: "sort values", text: `{{ range sort (list 3 1 2) }}{{ . }}{{ end }}`, want: "123"},
+		{name: "groupBy", text: `{{ range groupBy .posts "tag" }}{{ .Key }}:{{ len .Items }} {{ end }}`, want: "go:2 web:1 "},
+		{name: "default", text: `{{ .empty | default "fallback" }}|{{ "set" | default "fallback" }}`, want: "fallback|set"},
+		{name: "jsonify", text: `{{ jsonify (dict "a" 1) }}`, want: `{&#34;a&#34;:1}`},
+		{name: "math int", text: `{{ add 1 2 }} {{ sub 5 3 }} {{ mul 2 4 }} {{ div 7 2 }} {{ mod 7 2 }}`, want: "3 2 8 3 1"},
+		{name: "math float", text: `{{ div 7.0 2 }} {{ round 2.6 }}`, want: "3.5 3"},
+	}
+
+	for _, c := range cases {
+		got, err := execFuncs(t, c.text, data)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestDefaultFuncs_Errors(t *testing.T) {
+	cases := []struct {
+		name string
+		text string
+	}{
+		{name: "dict odd", text: `{{ dict "a" }}`},
+		{name: "div zero", text: `{{ div 1 0 }}`},
+		{name: "bad date", text: `{{ dateFormat "2006" "tomorrow" }}`},
+		{name: "where operator", text: `{{ where (list 1) "" "~" 1 }}`},
+		{name: "not a list", text: `{{ first 1 "abc" }}`},
+	}
+
+	for _, c := range cases {
+		if _, err := execFuncs(t, c.text, nil); err == nil {
+			t.Errorf("%s: expected an error", c.name)
+		}
+	}
+}
+
+func TestHTMLRenderer_Render_CustomFuncsOverrideDefaults(t *testing.T) {
+	tmp := t.TempDir()
+	templatePath := filepath.Join(tmp, "page.html")
+	content := `{{ define "root" }}{{ slugify "A B" }} {{ truncate 3 "abcdef" }}{{ end }}`
+	if err := os.WriteFile(templatePath, []byte(content), 0644); err != nil {
+		t.Fatalf("could not write template: %v", err)
+	}
+
+	renderer := rendering.HTMLRenderer{
+		CustomFuncs: template.FuncMap{"slugify": func(s string) string { return "custom" }},
+	}
+	out, err := renderer.Render(rendering.RenderContext{Template: templatePath})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if out != "custom abc…" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
@@ -3,28 +3,282 @@ package rendering
 import (
 	"bytes"
 	"html/template"
+	"io"
+	"io/fs"
+	"maps"
+	"slices"
+
+	"github.com/janmarkuslanger/ssgo/i18n"
+	"github.com/janmarkuslanger/ssgo/shortcode"
+	"github.com/janmarkuslanger/ssgo/toc"
+	"github.com/janmarkuslanger/ssgo/urls"
 )
 
+// HTMLRenderer renders html/template files. Parsed templates are cached and
+// reparsed when a file changes; Render is safe for concurrent use.
 type HTMLRenderer struct {
 	CustomFuncs template.FuncMap
 	Layout      []string
+	// FS loads layouts and templates from a file system such as embed.FS
+	// instead of the OS file system. Paths are then relative to FS.
+	FS fs.FS
+	// Partials are glob patterns or directories whose templates are available
+	// to every page, e.g. "partials/*.html" or "components".
+	Partials []string
+	// Shortcodes maps shortcode names to template files rendered with the
+	// shortcode.Shortcode as data, for {{< name >}} calls in content passed
+	// to the shortcodes template function.
+	Shortcodes map[string]string
+	// ShortcodeFuncs render shortcodes in Go and win over Shortcodes.
+	ShortcodeFuncs map[string]shortcode.Func
+	// Headings gives all headings in the output unique ids, optionally with
+	// anchor links, and exposes the page's table of contents as .TOC.
+	Headings *toc.Options
+	// Debug enables the dump function and, when the builder passes a
+	// RenderContext.Debug, records executed templates and missing map keys.
+	Debug bool
 }
 
 func (r HTMLRenderer) Render(ctx RenderContext) (output string, err error) {
+	var buf bytes.Buffer
+	if err := r.RenderTo(ctx, &buf); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// RenderTo executes the template directly into w. On error, w may already
+// hold partial output. With Headings set, the page is buffered instead.
+func (r HTMLRenderer) RenderTo(ctx RenderContext, w io.Writer) error {
+	files, partials, err := r.pageFiles(ctx.Template)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := r.parse(files, partials)
+	if err != nil {
+		return newRenderError(r.FS, files, err)
+	}
+	tmpl.Funcs(contextFuncs(ctx)).Funcs(r.shortcodeFuncs(ctx)).Funcs(r.debugFuncs(ctx)).Funcs(r.CustomFuncs)
+
+	return r.execute(tmpl, files, ctx, w)
+}
+
+// pageFiles returns the layouts, partials and template a page is parsed
+// from, and the partials on their own.
+func (r HTMLRenderer) pageFiles(name string) ([]string, []string, error) {
+	partials, err := r.partialFiles()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	layouts, err := layoutFiles(r.FS, r.Layout, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	files := []string{}
+	files = append(files, layouts...)
+	files = append(files, partials...)
+	files = append(files, name)
+	return files, partials, nil
+}
+
+// TemplateStamp changes whenever a file the template is rendered from
+// changes, including layouts, partials and shortcode templates.
+func (r HTMLRenderer) TemplateStamp(name string) (string, error) {
+	var files []string
+	if name == "" {
+		partials, err := r.partialFiles()
+		if err != nil {
+			return "", err
+		}
+		files = slices.Concat(r.Layout, partials)
+	} else {
+		var err error
+		if files, _, err = r.pageFiles(name); err != nil {
+			return "", err
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(r.Shortcodes)) {
+		files = append(files, r.Shortcodes[name])
+	}
+	return stampKey(r.FS, files)
+}
+
+// renderComponent executes the layouts with content, e.g. the output of a
+// FuncRenderer component, as the "content" template.
+func (r HTMLRenderer) renderComponent(ctx RenderContext, content template.HTML, w io.Writer) error {
+	partials, err := r.partialFiles()
+	if err != nil {
+		return err
+	}
+
 	files := []string{}
 	files = append(files, r.Layout...)
-	files = append(files, ctx.Template)
+	files = append(files, partials...)
 
-	tmpl := template.New("root").Funcs(r.CustomFuncs)
-	tmpl, err = tmpl.ParseFiles(files...)
+	tmpl, err := r.parse(files, partials)
 	if err != nil {
-		return "", err
+		return newRenderError(r.FS, files, err)
+	}
+	tmpl.Funcs(contextFuncs(ctx)).Funcs(r.shortcodeFuncs(ctx)).Funcs(r.debugFuncs(ctx)).Funcs(r.CustomFuncs)
+	tmpl.Funcs(template.FuncMap{"componentOutput": func() template.HTML { return content }})
+	if _, err := tmpl.New("content").Parse("{{ componentOutput }}"); err != nil {
+		return err
+	}
+
+	return r.execute(tmpl, files, ctx, w)
+}
+
+// parse returns a copy of the cached template set for files.
+func (r HTMLRenderer) parse(files, partials []string) (*template.Template, error) {
+	return htmlCache.get(r.FS, files, r.CustomFuncs, func() (*template.Template, error) {
+		if err := checkPartials(partials, files, r.definitions); err != nil {
+			return nil, err
+		}
+
+		tmpl := template.New("root").Funcs(DefaultFuncs()).Funcs(contextFuncs(RenderContext{})).Funcs(r.shortcodeFuncs(RenderContext{})).Funcs(r.debugFuncs(RenderContext{})).Funcs(r.CustomFuncs)
+		if r.FS != nil {
+			return tmpl.ParseFS(r.FS, files...)
+		}
+		return tmpl.ParseFiles(files...)
+	})
+}
+
+func (r HTMLRenderer) execute(tmpl *template.Template, files []string, ctx RenderContext, w io.Writer) error {
+	if r.Debug && ctx.Debug != nil {
+		if err := trace(tmpl, ctx.Debug); err != nil {
+			return err
+		}
+
+		var data any = ctx.Data
+		if r.Headings != nil {
+			withTOC := maps.Clone(ctx.Data)
+			if withTOC == nil {
+				withTOC = map[string]any{}
+			}
+			withTOC["TOC"] = toc.TOC{}
+			data = withTOC
+		}
+		ctx.Debug.Warnings = append(ctx.Debug.Warnings, r.missingKeys(tmpl, files, data)...)
+	}
+
+	if r.Headings != nil {
+		return r.renderHeadings(tmpl, files, ctx, w)
+	}
+
+	if err := tmpl.Execute(w, ctx.Data); err != nil {
+		return newRenderError(r.FS, files, err)
+	}
+
+	return nil
+}
+
+// renderHeadings gives the headings of the "content" template their ids
+// first, builds .TOC from them and renders the page with that content.
+// Headings of the layouts then get ids the content does not use, so the TOC
+// links keep pointing at the content. Without a "content" template the whole
+// page is processed at once.
+func (r HTMLRenderer) renderHeadings(tmpl *template.Template, files []string, ctx RenderContext, w io.Writer) error {
+	data := maps.Clone(ctx.Data)
+	if data == nil {
+		data = map[string]any{}
+	}
+	data["TOC"] = toc.TOC{}
+
+	if tmpl.Lookup("content") == nil {
+		first, err := tmpl.Clone()
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err := first.Execute(&buf, data); err != nil {
+			return newRenderError(r.FS, files, err)
+		}
+		data["TOC"] = toc.Extract(buf.String(), *r.Headings)
+		if ctx.TOC != nil {
+			*ctx.TOC = data["TOC"].(toc.TOC)
+		}
+
+		buf.Reset()
+		if err := tmpl.Execute(&buf, data); err != nil {
+			return newRenderError(r.FS, files, err)
+		}
+		out, _ := toc.Process(buf.String(), *r.Headings)
+		_, err = io.WriteString(w, out)
+		return err
+	}
+
+	// The content is rendered twice, so it can use .TOC itself.
+	var content string
+	for range 2 {
+		pass, err := tmpl.Clone()
+		if err != nil {
+			return err
+		}
+
+		var buf bytes.Buffer
+		if err := pass.ExecuteTemplate(&buf, "content", data); err != nil {
+			return newRenderError(r.FS, files, err)
+		}
+		var contents toc.TOC
+		content, contents = toc.Process(buf.String(), *r.Headings)
+		data["TOC"] = contents
+		if ctx.TOC != nil {
+			*ctx.TOC = contents
+		}
+	}
+
+	tmpl.Funcs(template.FuncMap{"headingsContent": func() template.HTML { return template.HTML(content) }})
+	if _, err := tmpl.New("content").Parse("{{ headingsContent }}"); err != nil {
+		return err
 	}
 
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, ctx.Data); err != nil {
-		return "", err
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return newRenderError(r.FS, files, err)
 	}
 
-	return buf.String(), nil
+	out, _ := toc.Process(buf.String(), *r.Headings)
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+func (r HTMLRenderer) HasTemplate(name string) bool {
+	return isFile(r.FS, name)
+}
+
+func contextFuncs(ctx RenderContext) template.FuncMap {
+	return template.FuncMap{
+		"site":      func() map[string]any { return ctx.Site },
+		"pageURL":   func() string { return ctx.URL },
+		"canonical": func() string { return ctx.Canonical },
+		"absURL":    func(p string) string { return urls.AbsURL(ctx.BaseURL, p) },
+		"relURL":    func(p string) string { return urls.RelURL(ctx.BaseURL, p) },
+		"absLangURL": func(p string) string {
+			return urls.AbsURL(langBaseURL(ctx), p)
+		},
+		"relLangURL": func(p string) string {
+			return urls.RelURL(langBaseURL(ctx), p)
+		},
+		"lang":       func() string { return ctx.Lang },
+		"alternates": func() []i18n.Alternate { return ctx.Alternates },
+		"T": func(key string, args ...any) string {
+			if ctx.Translate == nil {
+				return key
+			}
+			return ctx.Translate(key, args...)
+		},
+	}
+}
+
+func langBaseURL(ctx RenderContext) string {
+	if ctx.LangBaseURL != "" {
+		return ctx.LangBaseURL
+	}
+	return ctx.BaseURL
 }
@@ -1,13 +1,17 @@
 package rendering_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 	"text/template"
 
+	"github.com/janmarkuslanger/ssgo/i18n"
 	"github.com/janmarkuslanger/ssgo/rendering"
+	"github.com/janmarkuslanger/ssgo/toc"
 )
 
 func TestHTMLRenderer_Render_Success(t *testing.T) {
@@ -82,3 +86,350 @@ func TestHTMLRenderer_Render_UndefinedTemplate(t *testing.T) {
 		t.Fatal("expected error for undefined content block")
 	}
 }
+
+func TestHTMLRenderer_Render_SiteData(t *testing.T) {
+	tmp := t.TempDir()
+
+	templatePath := filepath.Join(tmp, "index.html")
+	err := os.WriteFile(templatePath, []byte(`{{ define "root" }}{{ with site }}{{ .title }}{{ end }}{{ end }}`), 0644)
+	if err != nil {
+		t.Fatalf("could not write template: %v", err)
+	}
+
+	renderer := rendering.HTMLRenderer{}
+	out, err := renderer.Render(rendering.RenderContext{
+		Site:     map[string]any{"title": "ssgo"},
+		Template: templatePath,
+	})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	if out != "ssgo" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestHTMLRenderer_HasTemplate(t *testing.T) {
+	tmp := t.TempDir()
+
+	templatePath := filepath.Join(tmp, "index.html")
+	if err := os.WriteFile(templatePath, []byte(`{{ define "content" }}{{ end }}`), 0644); err != nil {
+		t.Fatalf("could not write template: %v", err)
+	}
+
+	renderer := rendering.HTMLRenderer{}
+	if !renderer.HasTemplate(templatePath) {
+		t.Error("expected template to exist")
+	}
+	if renderer.HasTemplate(filepath.Join(tmp, "missing.html")) {
+		t.Error("expected missing template")
+	}
+	if renderer.HasTemplate(tmp) {
+		t.Error("directories are not templates")
+	}
+	if renderer.HasTemplate("") {
+		t.Error("empty name is not a template")
+	}
+}
+
+func TestHTMLRenderer_Render_URLFuncs(t *testing.T) {
+	tmp := t.TempDir()
+
+	templatePath := filepath.Join(tmp, "index.html")
+	tpl := `{{ define "root" }}<link rel="canonical" href="{{ canonical }}">` +
+		`<a href="{{ relURL "/blog/" }}">{{ absURL "/blog/" }}</a>{{ pageURL }}{{ end }}`
+	if err := os.WriteFile(templatePath, []byte(tpl), 0644); err != nil {
+		t.Fatalf("could not write template: %v", err)
+	}
+
+	renderer := rendering.HTMLRenderer{}
+	out, err := renderer.Render(rendering.RenderContext{
+		Template:  templatePath,
+		URL:       "/docs/about.html",
+		Canonical: "https://example.com/docs/about.html",
+		BaseURL:   "https://example.com/docs/",
+	})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	want := `<link rel="canonical" href="https://example.com/docs/about.html">` +
+		`<a href="/docs/blog/">https://example.com/docs/blog/</a>/docs/about.html`
+	if out != want {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out, want)
+	}
+}
+
+func TestHTMLRenderer_Render_I18nFuncs(t *testing.T) {
+	tmp := t.TempDir()
+
+	templatePath := filepath.Join(tmp, "index.html")
+	tpl := `{{ define "root" }}<html lang="{{ lang }}">{{ range alternates }}` +
+		`<link rel="alternate" hreflang="{{ .Lang }}" href="{{ .URL }}">{{ end }}` +
+		`<a href="{{ relLangURL "/blog/" }}">{{ T "posts" 2 }}</a>{{ end }}`
+	if err := os.WriteFile(templatePath, []byte(tpl), 0644); err != nil {
+		t.Fatalf("could not write template: %v", err)
+	}
+
+	renderer := rendering.HTMLRenderer{}
+	out, err := renderer.Render(rendering.RenderContext{
+		Template:    templatePath,
+		Lang:        "de",
+		LangBaseURL: "/de/",
+		Alternates: []i18n.Alternate{
+			{Lang: "en", URL: "/en/"},
+			{Lang: "de", URL: "/de/"},
+		},
+		Translate: func(key string, args ...any) string {
+			return fmt.Sprintf("%s:%v", key, args[0])
+		},
+	})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	want := `<html lang="de"><link rel="alternate" hreflang="en" href="/en/">` +
+		`<link rel="alternate" hreflang="de" href="/de/"><a href="/de/blog/">posts:2</a>`
+	if out != want {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out, want)
+	}
+}
+
+func TestHTMLRenderer_Render_TWithoutTranslations(t *testing.T) {
+	tmp := t.TempDir()
+
+	templatePath := filepath.Join(tmp, "index.html")
+	if err := os.WriteFile(templatePath, []byte(`{{ define "root" }}{{ T "nav.home" }}{{ end }}`), 0644); err != nil {
+		t.Fatalf("could not write template: %v", err)
+	}
+
+	out, err := rendering.HTMLRenderer{}.Render(rendering.RenderContext{Template: templatePath})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if out != "nav.home" {
+		t.Errorf("expected key as fallback, got %q", out)
+	}
+}
+
+func TestHTMLRenderer_Render_FS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layouts/base.html":   {Data: []byte(`{{ define "root" }}<main>{{ template "content" . }}</main>{{ end }}`)},
+		"templates/page.html": {Data: []byte(`{{ define "content" }}{{ .title }}{{ end }}`)},
+	}
+
+	renderer := rendering.HTMLRenderer{FS: fsys, Layout: []string{"layouts/base.html"}}
+	out, err := renderer.Render(rendering.RenderContext{
+		Data:     map[string]any{"title": "Embedded"},
+		Template: "templates/page.html",
+	})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if out != "<main>Embedded</main>" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	if !renderer.HasTemplate("templates/page.html") {
+		t.Error("expected template to exist in FS")
+	}
+	if renderer.HasTemplate("templates/missing.html") || renderer.HasTemplate("templates") {
+		t.Error("expected missing template in FS")
+	}
+
+	fsys["templates/page.html"] = &fstest.MapFile{Data: []byte(`{{ define "content" }}changed {{ .title }}{{ end }}`)}
+	out, err = renderer.Render(rendering.RenderContext{
+		Data:     map[string]any{"title": "Embedded"},
+		Template: "templates/page.html",
+	})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if out != "<main>changed Embedded</main>" {
+		t.Errorf("expected reloaded template, got %q", out)
+	}
+}
+
+func TestHTMLRenderer_Render_Partials(t *testing.T) {
+	tmp := t.TempDir()
+	files := map[string]string{
+		"layout.html":               `{{ define "root" }}{{ template "header" . }}{{ template "content" . }}{{ end }}`,
+		"partials/header.html":      `{{ define "header" }}<header>{{ .title }}</header>{{ end }}`,
+		"components/card/card.html": `{{ define "card" }}<div>{{ . }}</div>{{ end }}`,
+		"components/button.html":    `{{ define "button" }}<button>{{ . }}</button>{{ end }}`,
+		"page.html":                 `{{ define "content" }}{{ template "card" "a" }}{{ template "button" "b" }}{{ end }}`,
+	}
+	for name, content := range files {
+		p := filepath.Join(tmp, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir error: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+	}
+
+	renderer := rendering.HTMLRenderer{
+		Layout:   []string{filepath.Join(tmp, "layout.html")},
+		Partials: []string{filepath.Join(tmp, "partials", "*.html"), filepath.Join(tmp, "components")},
+	}
+	out, err := renderer.Render(rendering.RenderContext{
+		Data:     map[string]any{"title": "Hi"},
+		Template: filepath.Join(tmp, "page.html"),
+	})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	want := "<header>Hi</header><div>a</div><button>b</button>"
+	if out != want {
+		t.Errorf("unexpected output: %q, want %q", out, want)
+	}
+}
+
+func TestHTMLRenderer_Render_DuplicatePartial(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html":      {Data: []byte(`{{ define "root" }}{{ template "content" . }}{{ end }}`)},
+		"partials/a.html":  {Data: []byte(`{{ define "card" }}a{{ end }}`)},
+		"partials/b.html":  {Data: []byte(`{{ define "card" }}b{{ end }}`)},
+		"templates/p.html": {Data: []byte(`{{ define "content" }}{{ template "card" }}{{ end }}`)},
+	}
+
+	renderer := rendering.HTMLRenderer{FS: fsys, Layout: []string{"layout.html"}, Partials: []string{"partials"}}
+	_, err := renderer.Render(rendering.RenderContext{Template: "templates/p.html"})
+	if err == nil {
+		t.Fatal("expected duplicate template error")
+	}
+
+	want := `template "card" is defined in both partials/a.html and partials/b.html`
+	if err.Error() != want {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestHTMLRenderer_Render_PartialRedefinesPage(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html":      {Data: []byte(`{{ define "root" }}{{ template "content" . }}{{ end }}`)},
+		"partials/a.html":  {Data: []byte(`{{ define "content" }}partial{{ end }}`)},
+		"templates/p.html": {Data: []byte(`{{ define "content" }}page{{ end }}`)},
+	}
+
+	renderer := rendering.HTMLRenderer{FS: fsys, Layout: []string{"layout.html"}, Partials: []string{"partials"}}
+	_, err := renderer.Render(rendering.RenderContext{Template: "templates/p.html"})
+
+	want := `template "content" is defined in both templates/p.html and partials/a.html`
+	if err == nil || err.Error() != want {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	errs := renderer.ValidateTemplates([]string{"templates/p.html"})
+	if len(errs) != 1 || errs[0].Error() != want {
+		t.Errorf("unexpected validation errors: %v", errs)
+	}
+}
+
+func TestHTMLRenderer_Render_PartialAdded(t *testing.T) {
+	tmp := t.TempDir()
+	write := func(name, content string) {
+		p := filepath.Join(tmp, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir error: %v", err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf("could not write %s: %v", name, err)
+		}
+	}
+	write("partials/a.html", `{{ define "a" }}A{{ end }}`)
+	write("page.html", `{{ define "root" }}{{ template "a" }}{{ if false }}{{ template "b" }}{{ end }}{{ end }}`)
+
+	renderer := rendering.HTMLRenderer{Partials: []string{filepath.Join(tmp, "partials")}}
+	ctx := rendering.RenderContext{Template: filepath.Join(tmp, "page.html")}
+	if _, err := renderer.Render(ctx); err == nil {
+		t.Fatal("expected error for missing partial b")
+	}
+
+	write("partials/b.html", `{{ define "b" }}B{{ end }}`)
+	out, err := renderer.Render(ctx)
+	if err != nil {
+		t.Fatalf("new partial was not picked up: %v", err)
+	}
+	if out != "A" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestHTMLRenderer_Render_PartialsNoMatch(t *testing.T) {
+	renderer := rendering.HTMLRenderer{FS: fstest.MapFS{}, Partials: []string{"partials/*.html"}}
+
+	_, err := renderer.Render(rendering.RenderContext{Template: "p.html"})
+	if err == nil || !strings.Contains(err.Error(), "matches no files") {
+		t.Fatalf("expected no match error, got %v", err)
+	}
+}
+
+func TestHTMLRenderer_RenderTo(t *testing.T) {
+	fsys := fstest.MapFS{
+		"page.html": {Data: []byte(`{{ define "root" }}<p>{{ .n }}</p>{{ end }}`)},
+	}
+
+	var b strings.Builder
+	err := rendering.HTMLRenderer{FS: fsys}.RenderTo(rendering.RenderContext{Template: "page.html", Data: map[string]any{"n": 1}}, &b)
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if b.String() != "<p>1</p>" {
+		t.Errorf("unexpected output: %q", b.String())
+	}
+}
+
+func TestHTMLRenderer_Render_Headings(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`{{ define "root" }}<h1>Site</h1><nav>{{ .TOC.HTML }}</nav>{{ template "content" . }}{{ end }}`)},
+		"page.html":   {Data: []byte(`{{ define "content" }}<h2>Intro</h2><h3>Setup &amp; run</h3><h2>Intro</h2>{{ end }}`)},
+	}
+	r := rendering.HTMLRenderer{
+		FS:       fsys,
+		Layout:   []string{"layout.html"},
+		Headings: &toc.Options{AnchorLinks: true},
+	}
+
+	got, err := r.Render(rendering.RenderContext{Template: "page.html"})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	want := `<h1 id="site">Site <a class="anchor" href="#site" aria-hidden="true">#</a></h1>` +
+		`<nav><ul><li><a href="#intro">Intro</a><ul><li><a href="#setup-run">Setup &amp; run</a></li></ul></li>` +
+		`<li><a href="#intro-1">Intro</a></li></ul></nav>` +
+		`<h2 id="intro">Intro <a class="anchor" href="#intro" aria-hidden="true">#</a></h2>` +
+		`<h3 id="setup-run">Setup &amp; run <a class="anchor" href="#setup-run" aria-hidden="true">#</a></h3>` +
+		`<h2 id="intro-1">Intro <a class="anchor" href="#intro-1" aria-hidden="true">#</a></h2>`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
+
+func TestHTMLRenderer_Render_HeadingsLayoutCollision(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`{{ define "root" }}<header><h2>Intro</h2></header>{{ .TOC.HTML }}{{ template "content" . }}{{ end }}`)},
+		"page.html":   {Data: []byte(`{{ define "content" }}<h2>Intro</h2>{{ end }}`)},
+	}
+	r := rendering.HTMLRenderer{FS: fsys, Layout: []string{"layout.html"}, Headings: &toc.Options{}}
+
+	var contents toc.TOC
+	got, err := r.Render(rendering.RenderContext{Template: "page.html", TOC: &contents})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if len(contents) != 1 || contents[0].ID != "intro" {
+		t.Errorf("unexpected TOC in context: %+v", contents)
+	}
+
+	want := `<header><h2 id="intro-1">Intro</h2></header>` +
+		`<ul><li><a href="#intro">Intro</a></li></ul>` +
+		`<h2 id="intro">Intro</h2>`
+	if got != want {
+		t.Errorf("got  %s\nwant %s", got, want)
+	}
+}
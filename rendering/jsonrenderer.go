@@ -0,0 +1,55 @@
+package rendering
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+// JSONRenderer serialises the page data as JSON, e.g. for static API
+// endpoints. Map keys are sorted, so the output is reproducible.
+type JSONRenderer struct {
+	// Project maps the render context to the value that is serialised.
+	// Defaults to the page data.
+	Project func(ctx RenderContext) (any, error)
+	// Fields limits the page data to the given top-level keys. It is ignored
+	// when Project is set.
+	Fields []string
+	// Pretty indents the output with two spaces.
+	Pretty bool
+}
+
+func (r JSONRenderer) Render(ctx RenderContext) (output string, err error) {
+	var buf bytes.Buffer
+	if err := r.RenderTo(ctx, &buf); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+func (r JSONRenderer) RenderTo(ctx RenderContext, w io.Writer) (err error) {
+	var v any = ctx.Data
+	switch {
+	case r.Project != nil:
+		v, err = r.Project(ctx)
+		if err != nil {
+			return err
+		}
+	case len(r.Fields) > 0:
+		fields := make(map[string]any, len(r.Fields))
+		for _, f := range r.Fields {
+			if value, ok := ctx.Data[f]; ok {
+				fields[f] = value
+			}
+		}
+		v = fields
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if r.Pretty {
+		enc.SetIndent("", "  ")
+	}
+	return enc.Encode(v)
+}
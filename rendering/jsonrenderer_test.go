@@ -0,0 +1,73 @@
+package rendering_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/janmarkuslanger/ssgo/rendering"
+)
+
+func TestJSONRenderer_Render_Compact(t *testing.T) {
+	out, err := rendering.JSONRenderer{}.Render(rendering.RenderContext{
+		Data: map[string]any{"title": "A & B", "id": 1, "tags": []string{"go"}},
+	})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	want := `{"id":1,"tags":["go"],"title":"A & B"}` + "\n"
+	if out != want {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestJSONRenderer_Render_PrettyFields(t *testing.T) {
+	r := rendering.JSONRenderer{Fields: []string{"title", "slug", "missing"}, Pretty: true}
+	out, err := r.Render(rendering.RenderContext{
+		Data: map[string]any{"title": "Hello", "slug": "hello", "body": "secret"},
+	})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	want := "{\n  \"slug\": \"hello\",\n  \"title\": \"Hello\"\n}\n"
+	if out != want {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestJSONRenderer_Render_Project(t *testing.T) {
+	r := rendering.JSONRenderer{
+		Project: func(ctx rendering.RenderContext) (any, error) {
+			return map[string]any{"url": ctx.Canonical, "title": ctx.Data["title"]}, nil
+		},
+		Fields: []string{"ignored"},
+	}
+	out, err := r.Render(rendering.RenderContext{
+		Data:      map[string]any{"title": "Hello"},
+		Canonical: "https://example.com/hello",
+	})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	want := `{"title":"Hello","url":"https://example.com/hello"}` + "\n"
+	if out != want {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestJSONRenderer_Render_Errors(t *testing.T) {
+	projectErr := errors.New("boom")
+	_, err := rendering.JSONRenderer{
+		Project: func(ctx rendering.RenderContext) (any, error) { return nil, projectErr },
+	}.Render(rendering.RenderContext{})
+	if !errors.Is(err, projectErr) {
+		t.Errorf("expected projection error, got %v", err)
+	}
+
+	_, err = rendering.JSONRenderer{}.Render(rendering.RenderContext{Data: map[string]any{"f": func() {}}})
+	if err == nil {
+		t.Error("expected an encoding error")
+	}
+}
@@ -0,0 +1,83 @@
+package rendering
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"regexp"
+	"slices"
+)
+
+// extendsPattern matches a leading {{/* extends "path" */}} comment.
+var extendsPattern = regexp.MustCompile(`^\s*\{\{-?\s*/\*\s*extends\s+"([^"]+)"\s*\*/\s*-?\}\}`)
+
+// maxDirectiveSize limits how much of a file is read to find its extends
+// directive.
+const maxDirectiveSize = 1024
+
+// layoutFiles returns the layouts a template is parsed with, ordered from the
+// outermost one. A template starting with {{/* extends "path" */}} adds that
+// layout, which can extend another one, on top of the renderer's Layout
+// list. Later files override blocks of earlier ones.
+func layoutFiles(fsys fs.FS, layouts []string, tmpl string) ([]string, error) {
+	var chain []string
+	seen := map[string]bool{tmpl: true}
+
+	for current := tmpl; ; {
+		parent, err := extends(fsys, current)
+		if err != nil {
+			return nil, err
+		}
+		if parent == "" {
+			break
+		}
+		if seen[parent] {
+			return nil, fmt.Errorf("layout cycle: %s extends %s", current, parent)
+		}
+		seen[parent] = true
+		chain = append([]string{parent}, chain...)
+		current = parent
+	}
+
+	if len(chain) == 0 {
+		return layouts, nil
+	}
+
+	files := append([]string{}, layouts...)
+	for _, f := range chain {
+		if !slices.Contains(files, f) {
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// extends returns the parent layout declared by a file, if any. Missing
+// files are left to the parser to report.
+func extends(fsys fs.FS, name string) (string, error) {
+	var (
+		f   fs.File
+		err error
+	)
+	if fsys != nil {
+		f, err = fsys.Open(name)
+	} else {
+		f, err = os.Open(name)
+	}
+	if err != nil {
+		return "", nil
+	}
+	defer f.Close()
+
+	head := make([]byte, maxDirectiveSize)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+
+	if m := extendsPattern.FindSubmatch(head[:n]); m != nil {
+		return string(m[1]), nil
+	}
+	return "", nil
+}
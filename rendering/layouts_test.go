@@ -0,0 +1,110 @@
+package rendering_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/janmarkuslanger/ssgo/rendering"
+)
+
+func nestedLayoutsFS() fstest.MapFS {
+	return fstest.MapFS{
+		"layouts/base.html": {Data: []byte(`{{ define "root" }}<title>{{ block "title" . }}Site{{ end }}</title>{{ block "head" . }}{{ end }}<body>{{ template "content" . }}{{ block "scripts" . }}<script src="/app.js"></script>{{ end }}</body>{{ end }}`)},
+		"layouts/blog.html": {Data: []byte(`{{/* extends "layouts/base.html" */}}
+{{ define "head" }}<link rel="alternate" href="/feed.xml">{{ end }}
+{{ define "content" }}<nav>blog</nav><main>{{ block "main" . }}{{ end }}</main>{{ end }}`)},
+		"layouts/docs.html": {Data: []byte(`{{/* extends "layouts/base.html" */}}
+{{ define "content" }}<nav>docs</nav>{{ block "main" . }}{{ end }}{{ end }}
+{{ define "scripts" }}{{ "" }}{{ end }}`)},
+		"pages/post.html": {Data: []byte(`{{- /* extends "layouts/blog.html" */ -}}
+{{ define "title" }}{{ .title }} – Blog{{ end }}
+{{ define "main" }}<h1>{{ .title }}</h1>{{ end }}`)},
+		"pages/guide.html":  {Data: []byte(`{{/* extends "layouts/docs.html" */}}{{ define "main" }}Guide{{ end }}`)},
+		"pages/plain.html":  {Data: []byte(`{{ define "content" }}Plain{{ end }}`)},
+		"pages/loop-a.html": {Data: []byte(`{{/* extends "pages/loop-b.html" */}}`)},
+		"pages/loop-b.html": {Data: []byte(`{{/* extends "pages/loop-a.html" */}}`)},
+	}
+}
+
+func TestHTMLRenderer_Render_NestedLayouts(t *testing.T) {
+	r := rendering.HTMLRenderer{FS: nestedLayoutsFS(), Layout: []string{"layouts/base.html"}}
+
+	cases := []struct {
+		template string
+		want     string
+	}{
+		{
+			template: "pages/post.html",
+			want:     `<title>Hello – Blog</title><link rel="alternate" href="/feed.xml"><body><nav>blog</nav><main><h1>Hello</h1></main><script src="/app.js"></script></body>`,
+		},
+		{
+			template: "pages/guide.html",
+			want:     `<title>Site</title><body><nav>docs</nav>Guide</body>`,
+		},
+		{
+			template: "pages/plain.html",
+			want:     `<title>Site</title><body>Plain<script src="/app.js"></script></body>`,
+		},
+	}
+
+	for _, c := range cases {
+		out, err := r.Render(rendering.RenderContext{Template: c.template, Data: map[string]any{"title": "Hello"}})
+		if err != nil {
+			t.Fatalf("%s: rendering failed: %v", c.template, err)
+		}
+		if out != c.want {
+			t.Errorf("%s: unexpected output:\n%s\nexpected:\n%s", c.template, out, c.want)
+		}
+	}
+}
+
+func TestHTMLRenderer_Render_ExtendsWithoutLayout(t *testing.T) {
+	r := rendering.HTMLRenderer{FS: nestedLayoutsFS()}
+
+	out, err := r.Render(rendering.RenderContext{Template: "pages/guide.html"})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if out != `<title>Site</title><body><nav>docs</nav>Guide</body>` {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestHTMLRenderer_Render_LayoutCycle(t *testing.T) {
+	r := rendering.HTMLRenderer{FS: nestedLayoutsFS()}
+
+	_, err := r.Render(rendering.RenderContext{Template: "pages/loop-a.html"})
+	if err == nil || !strings.Contains(err.Error(), "layout cycle") {
+		t.Fatalf("expected layout cycle error, got %v", err)
+	}
+}
+
+func TestHTMLRenderer_ValidateTemplates_Extends(t *testing.T) {
+	fsys := nestedLayoutsFS()
+	fsys["pages/broken.html"] = &fstest.MapFile{Data: []byte(`{{/* extends "layouts/missing.html" */}}{{ define "content" }}{{ end }}`)}
+
+	r := rendering.HTMLRenderer{FS: fsys}
+	errs := r.ValidateTemplates([]string{"pages/post.html", "pages/guide.html", "pages/broken.html", "pages/loop-a.html"})
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 problems, got %v", errs)
+	}
+	if !strings.Contains(errs[0].Error(), "layouts/missing.html") || !strings.Contains(errs[1].Error(), "layout cycle") {
+		t.Errorf("unexpected problems: %v", errs)
+	}
+}
+
+func TestTextRenderer_Render_Extends(t *testing.T) {
+	fsys := fstest.MapFS{
+		"base.xml": {Data: []byte(`{{ define "root" }}<feed>{{ block "entries" . }}{{ end }}</feed>{{ end }}`)},
+		"blog.xml": {Data: []byte(`{{/* extends "base.xml" */}}{{ define "entries" }}<entry>{{ .title }}</entry>{{ end }}`)},
+	}
+
+	out, err := rendering.TextRenderer{FS: fsys}.Render(rendering.RenderContext{Template: "blog.xml", Data: map[string]any{"title": "A & B"}})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if out != "<feed><entry>A & B</entry></feed>" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
@@ -0,0 +1,309 @@
+package rendering
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"maps"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/janmarkuslanger/ssgo/i18n"
+	"github.com/janmarkuslanger/ssgo/toc"
+	"github.com/yuin/goldmark"
+)
+
+// Middleware wraps a renderer, e.g. to change its context or output.
+type Middleware func(next Renderer) Renderer
+
+// RendererFunc adapts a function to the Renderer interface.
+type RendererFunc func(ctx RenderContext) (string, error)
+
+func (f RendererFunc) Render(ctx RenderContext) (string, error) {
+	return f(ctx)
+}
+
+// Chain wraps r in middleware. The first middleware is the outermost, so it
+// sees the context first and the output last. Template checks, validation
+// and stamps are passed through to r, also for the next renderer each
+// middleware wraps.
+func Chain(r Renderer, middleware ...Middleware) Renderer {
+	wrapped := r
+	for i := len(middleware) - 1; i >= 0; i-- {
+		wrapped = chained{Renderer: middleware[i](wrapped), base: r}
+	}
+	return chained{Renderer: wrapped, base: r}
+}
+
+type chained struct {
+	Renderer
+	base Renderer
+}
+
+func (c chained) HasTemplate(name string) bool {
+	if tc, ok := c.base.(TemplateChecker); ok {
+		return tc.HasTemplate(name)
+	}
+	return true
+}
+
+func (c chained) ValidateTemplates(templates []string) []error {
+	if tv, ok := c.base.(TemplateValidator); ok {
+		return tv.ValidateTemplates(templates)
+	}
+	return nil
+}
+
+func (c chained) TemplateStamp(name string) (string, error) {
+	if ts, ok := c.base.(TemplateStamper); ok {
+		return ts.TemplateStamp(name)
+	}
+	return "", nil
+}
+
+// Pipe renders with each renderer in turn and passes the output to the next
+// one as template.HTML under key in the data, e.g. a Markdown body into an
+// HTML layout. It returns the output of the last renderer.
+func Pipe(key string, renderers ...Renderer) Renderer {
+	return pipe{key: key, renderers: renderers}
+}
+
+type pipe struct {
+	key       string
+	renderers []Renderer
+}
+
+func (p pipe) Render(ctx RenderContext) (string, error) {
+	var out string
+	for i, r := range p.renderers {
+		if i > 0 {
+			ctx.Data = maps.Clone(ctx.Data)
+			if ctx.Data == nil {
+				ctx.Data = map[string]any{}
+			}
+			ctx.Data[p.key] = template.HTML(out)
+		}
+
+		var err error
+		if out, err = r.Render(ctx); err != nil {
+			return "", err
+		}
+	}
+	return out, nil
+}
+
+// TemplateStamp combines the stamps of the piped renderers.
+func (p pipe) TemplateStamp(name string) (string, error) {
+	var b strings.Builder
+	for _, r := range p.renderers {
+		ts, ok := r.(TemplateStamper)
+		if !ok {
+			continue
+		}
+		stamp, err := ts.TemplateStamp(name)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(stamp)
+		b.WriteString("\x01")
+	}
+	return b.String(), nil
+}
+
+// Timing reports how long every render takes.
+func Timing(report func(ctx RenderContext, d time.Duration)) Middleware {
+	return func(next Renderer) Renderer {
+		return RendererFunc(func(ctx RenderContext) (string, error) {
+			start := time.Now()
+			out, err := next.Render(ctx)
+			report(ctx, time.Since(start))
+			return out, err
+		})
+	}
+}
+
+// Cache reuses the output of earlier renders with the same context: template,
+// URLs, language, alternates, data and site data. Data is compared by its
+// JSON encoding, so pointers are compared by what they point to; pages whose
+// data cannot be encoded, e.g. because it holds funcs or channels, are not
+// cached. When the wrapped renderer implements TemplateStamper, edits to its
+// template files invalidate the output. Renders with a RenderContext.Debug
+// are not cached, and errors are not cached. At most size outputs are kept,
+// dropping the oldest first; size <= 0 keeps all of them.
+func Cache(size int) Middleware {
+	return func(next Renderer) Renderer {
+		var (
+			mu    sync.Mutex
+			cache = make(map[[sha256.Size]byte]cachedOutput)
+			order [][sha256.Size]byte
+		)
+
+		return RendererFunc(func(ctx RenderContext) (string, error) {
+			if ctx.Debug != nil {
+				return next.Render(ctx)
+			}
+
+			key, ok := outputKey(next, ctx)
+			if !ok {
+				return next.Render(ctx)
+			}
+
+			mu.Lock()
+			cached, ok := cache[key]
+			mu.Unlock()
+			if ok {
+				if ctx.TOC != nil {
+					*ctx.TOC = cached.toc
+				}
+				return cached.out, nil
+			}
+
+			var contents toc.TOC
+			inner := ctx
+			inner.TOC = &contents
+			out, err := next.Render(inner)
+			if err != nil {
+				return "", err
+			}
+			if ctx.TOC != nil {
+				*ctx.TOC = contents
+			}
+
+			mu.Lock()
+			if _, ok := cache[key]; !ok {
+				if size > 0 && len(order) >= size {
+					delete(cache, order[0])
+					order = order[1:]
+				}
+				order = append(order, key)
+			}
+			cache[key] = cachedOutput{out: out, toc: contents}
+			mu.Unlock()
+			return out, nil
+		})
+	}
+}
+
+type cachedOutput struct {
+	out string
+	toc toc.TOC
+}
+
+// outputKey hashes everything in ctx that can change the output of r. It
+// reports false when the context cannot be encoded or the stamps fail.
+func outputKey(r Renderer, ctx RenderContext) ([sha256.Size]byte, bool) {
+	var stamp string
+	if ts, ok := r.(TemplateStamper); ok {
+		var err error
+		if stamp, err = ts.TemplateStamp(ctx.Template); err != nil {
+			return [sha256.Size]byte{}, false
+		}
+	}
+
+	// encoding/json sorts map keys, so equal data hashes equally.
+	key, err := json.Marshal(struct {
+		Template, URL, Canonical, BaseURL, LangBaseURL, Lang string
+		Alternates                                           []i18n.Alternate
+		Data, Site                                           map[string]any
+		Stamp                                                string
+	}{ctx.Template, ctx.URL, ctx.Canonical, ctx.BaseURL, ctx.LangBaseURL, ctx.Lang, ctx.Alternates, ctx.Data, ctx.Site, stamp})
+	if err != nil {
+		return [sha256.Size]byte{}, false
+	}
+	return sha256.Sum256(key), true
+}
+
+// DecorateErrors replaces render errors with the result of decorate, e.g.
+// to add the page URL.
+func DecorateErrors(decorate func(ctx RenderContext, err error) error) Middleware {
+	return func(next Renderer) Renderer {
+		return RendererFunc(func(ctx RenderContext) (string, error) {
+			out, err := next.Render(ctx)
+			if err != nil {
+				return "", decorate(ctx, err)
+			}
+			return out, nil
+		})
+	}
+}
+
+// Defaults fills data keys that are missing from the page data.
+func Defaults(values map[string]any) Middleware {
+	return func(next Renderer) Renderer {
+		return RendererFunc(func(ctx RenderContext) (string, error) {
+			data := make(map[string]any, len(values)+len(ctx.Data))
+			maps.Copy(data, values)
+			maps.Copy(data, ctx.Data)
+			ctx.Data = data
+			return next.Render(ctx)
+		})
+	}
+}
+
+// Markdown converts the string values of keys in the data from Markdown to
+// template.HTML before rendering.
+func Markdown(keys ...string) Middleware {
+	return func(next Renderer) Renderer {
+		return RendererFunc(func(ctx RenderContext) (string, error) {
+			ctx.Data = maps.Clone(ctx.Data)
+			for _, key := range keys {
+				s, ok := ctx.Data[key].(string)
+				if !ok {
+					continue
+				}
+
+				var buf bytes.Buffer
+				if err := goldmark.Convert([]byte(s), &buf); err != nil {
+					return "", fmt.Errorf("markdown %q: %w", key, err)
+				}
+				ctx.Data[key] = template.HTML(buf.String())
+			}
+			return next.Render(ctx)
+		})
+	}
+}
+
+// Minify removes HTML comments and collapses whitespace in the output.
+// pre, textarea, script and style elements are kept as they are.
+func Minify() Middleware {
+	return func(next Renderer) Renderer {
+		return RendererFunc(func(ctx RenderContext) (string, error) {
+			out, err := next.Render(ctx)
+			if err != nil {
+				return "", err
+			}
+			return minifyHTML(out), nil
+		})
+	}
+}
+
+var (
+	rawElementPattern = regexp.MustCompile(`(?is)<(pre|textarea|script|style)\b.*?</(?:pre|textarea|script|style)\s*>`)
+	htmlCommentRegexp = regexp.MustCompile(`(?s)<!--(?:[^\[].*?)?-->`)
+	whitespacePattern = regexp.MustCompile(`\s+`)
+	blockTagPattern   = regexp.MustCompile(`(?i)\s*(</?(?:!doctype|html|head|body|meta|link|title|base|div|p|ul|ol|li|dl|dt|dd|section|article|header|footer|nav|main|aside|h[1-6]|table|thead|tbody|tfoot|tr|td|th|form|fieldset|figure|figcaption|blockquote|hr|br|pre|textarea|script|style|noscript)\b[^>]*>)\s*`)
+)
+
+func minifyHTML(s string) string {
+	var b strings.Builder
+	pos := 0
+	for _, loc := range rawElementPattern.FindAllStringIndex(s, -1) {
+		b.WriteString(strings.TrimSpace(minifyText(s[pos:loc[0]])))
+		b.WriteString(s[loc[0]:loc[1]])
+		pos = loc[1]
+	}
+	b.WriteString(strings.TrimSpace(minifyText(s[pos:])))
+
+	return b.String()
+}
+
+// minifyText minifies HTML without raw elements.
+func minifyText(s string) string {
+	s = htmlCommentRegexp.ReplaceAllString(s, "")
+	s = whitespacePattern.ReplaceAllString(s, " ")
+	return blockTagPattern.ReplaceAllString(s, "$1")
+}
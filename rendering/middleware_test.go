@@ -0,0 +1,271 @@
+package rendering_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/janmarkuslanger/ssgo/i18n"
+	"github.com/janmarkuslanger/ssgo/rendering"
+	"github.com/janmarkuslanger/ssgo/toc"
+)
+
+// trace records the order in which middleware runs.
+func trace(name string, log *[]string) rendering.Middleware {
+	return func(next rendering.Renderer) rendering.Renderer {
+		return rendering.RendererFunc(func(ctx rendering.RenderContext) (string, error) {
+			*log = append(*log, name+" in")
+			out, err := next.Render(ctx)
+			*log = append(*log, name+" out")
+			return out, err
+		})
+	}
+}
+
+func TestChain_Order(t *testing.T) {
+	var log []string
+	r := rendering.Chain(rendering.RendererFunc(func(ctx rendering.RenderContext) (string, error) {
+		log = append(log, "render")
+		return "ok", nil
+	}), trace("a", &log), trace("b", &log))
+
+	if _, err := r.Render(rendering.RenderContext{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := strings.Join(log, ","); got != "a in,b in,render,b out,a out" {
+		t.Errorf("unexpected order: %s", got)
+	}
+}
+
+func TestChain_PassesTemplateChecks(t *testing.T) {
+	base := rendering.HTMLRenderer{FS: fstest.MapFS{"page.html": {Data: []byte(`{{ define "root" }}x{{ end }}`)}}}
+	r := rendering.Chain(base, rendering.Minify())
+
+	tc, ok := r.(rendering.TemplateChecker)
+	if !ok || !tc.HasTemplate("page.html") || tc.HasTemplate("missing.html") {
+		t.Error("expected template checks of the wrapped renderer")
+	}
+	tv, ok := r.(rendering.TemplateValidator)
+	if !ok || len(tv.ValidateTemplates([]string{"page.html"})) != 0 || len(tv.ValidateTemplates([]string{"missing.html"})) != 1 {
+		t.Error("expected validation of the wrapped renderer")
+	}
+}
+
+func TestPipe(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html": {Data: []byte(`{{ define "root" }}<main>{{ .body }}</main>{{ end }}`)},
+		"page.html":   {Data: []byte(`{{ define "content" }}{{ end }}`)},
+	}
+	body := rendering.RendererFunc(func(ctx rendering.RenderContext) (string, error) {
+		return fmt.Sprintf("<h1>%s</h1>", ctx.Data["title"]), nil
+	})
+	r := rendering.Pipe("body", body, rendering.HTMLRenderer{FS: fsys, Layout: []string{"layout.html"}})
+
+	out, err := r.Render(rendering.RenderContext{Template: "page.html", Data: map[string]any{"title": "Hi"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out != "<main><h1>Hi</h1></main>" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	calls := 0
+	echo := rendering.RendererFunc(func(ctx rendering.RenderContext) (string, error) {
+		calls++
+		if ctx.Data["fail"] == true {
+			return "", errors.New("boom")
+		}
+		return fmt.Sprintf("%v|%v", ctx.Data["title"], ctx.Data["body"]), nil
+	})
+
+	var timed string
+	r := rendering.Chain(echo,
+		rendering.Timing(func(ctx rendering.RenderContext, d time.Duration) { timed = ctx.URL }),
+		rendering.DecorateErrors(func(ctx rendering.RenderContext, err error) error {
+			return fmt.Errorf("%s: %w", ctx.URL, err)
+		}),
+		rendering.Cache(0),
+		rendering.Defaults(map[string]any{"title": "Untitled", "body": "-"}),
+		rendering.Markdown("body"),
+	)
+
+	ctx := rendering.RenderContext{URL: "/a", Data: map[string]any{"body": "**x**"}}
+	for i := 0; i < 2; i++ {
+		out, err := r.Render(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out != "Untitled|<p><strong>x</strong></p>\n" {
+			t.Errorf("unexpected output: %q", out)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("expected a cached render, got %d calls", calls)
+	}
+	if timed != "/a" {
+		t.Errorf("expected timing report, got %q", timed)
+	}
+	if _, ok := ctx.Data["title"]; ok {
+		t.Error("the page data should not be modified")
+	}
+
+	_, err := r.Render(rendering.RenderContext{URL: "/b", Data: map[string]any{"fail": true}})
+	if err == nil || err.Error() != "/b: boom" {
+		t.Errorf("expected decorated error, got %v", err)
+	}
+}
+
+func TestCache(t *testing.T) {
+	type post struct{ Title string }
+
+	calls := 0
+	r := rendering.Chain(rendering.RendererFunc(func(ctx rendering.RenderContext) (string, error) {
+		calls++
+		if p, ok := ctx.Data["post"].(*post); ok {
+			return p.Title, nil
+		}
+		return "", nil
+	}), rendering.Cache(2))
+
+	render := func(data map[string]any) string {
+		t.Helper()
+		out, err := r.Render(rendering.RenderContext{Data: data})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out
+	}
+
+	p := &post{Title: "A"}
+	render(map[string]any{"post": p})
+	render(map[string]any{"post": &post{Title: "A"}})
+	if calls != 1 {
+		t.Errorf("equal pointer data should hit the cache, got %d calls", calls)
+	}
+
+	p.Title = "B"
+	if out := render(map[string]any{"post": p}); out != "B" {
+		t.Errorf("changed pointee returned stale output %q", out)
+	}
+
+	render(map[string]any{"post": &post{Title: "C"}})
+	render(map[string]any{"post": &post{Title: "A"}})
+	if calls != 4 {
+		t.Errorf("oldest entry should have been dropped, got %d calls", calls)
+	}
+
+	calls = 0
+	render(map[string]any{"f": func() {}})
+	render(map[string]any{"f": func() {}})
+	if calls != 2 {
+		t.Errorf("data that cannot be encoded must not be cached, got %d calls", calls)
+	}
+}
+
+func TestCache_Context(t *testing.T) {
+	dir := t.TempDir()
+	tmplPath := filepath.Join(dir, "page.html")
+	layoutPath := filepath.Join(dir, "layout.html")
+	writeTemplate := func(path, content string) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTemplate(layoutPath, `{{ define "root" }}[{{ template "content" . }}]{{ end }}`)
+	writeTemplate(tmplPath, `{{ define "content" }}{{ .title }} {{ site.name }}{{ end }}`)
+
+	r := rendering.Chain(rendering.HTMLRenderer{Layout: []string{layoutPath}}, rendering.Cache(0), rendering.Minify())
+	render := func(ctx rendering.RenderContext) string {
+		t.Helper()
+		ctx.Template = tmplPath
+		out, err := r.Render(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return out
+	}
+
+	data := map[string]any{"title": "A"}
+	if out := render(rendering.RenderContext{Data: data, Site: map[string]any{"name": "x"}}); out != "[A x]" {
+		t.Fatalf("unexpected output %q", out)
+	}
+
+	writeTemplate(tmplPath, `{{ define "content" }}<b>{{ .title }}</b> {{ site.name }}{{ end }}`)
+	if out := render(rendering.RenderContext{Data: data, Site: map[string]any{"name": "x"}}); out != "[<b>A</b> x]" {
+		t.Errorf("edited template returned stale output %q", out)
+	}
+
+	writeTemplate(layoutPath, `{{ define "root" }}({{ template "content" . }}){{ end }}`)
+	if out := render(rendering.RenderContext{Data: data, Site: map[string]any{"name": "x"}}); out != "(<b>A</b> x)" {
+		t.Errorf("edited layout returned stale output %q", out)
+	}
+
+	if out := render(rendering.RenderContext{Data: data, Site: map[string]any{"name": "y"}}); out != "(<b>A</b> y)" {
+		t.Errorf("changed site data returned stale output %q", out)
+	}
+}
+
+func TestCache_TOC(t *testing.T) {
+	fsys := fstest.MapFS{"page.html": {Data: []byte(`{{ define "root" }}<h2>Intro</h2>{{ end }}`)}}
+	r := rendering.Chain(rendering.HTMLRenderer{FS: fsys, Headings: &toc.Options{}}, rendering.Cache(0))
+
+	for range 2 {
+		var contents toc.TOC
+		if _, err := r.Render(rendering.RenderContext{Template: "page.html", TOC: &contents}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(contents) != 1 || contents[0].ID != "intro" {
+			t.Errorf("unexpected TOC %+v", contents)
+		}
+	}
+}
+
+func TestCache_Bypass(t *testing.T) {
+	calls := 0
+	r := rendering.Chain(rendering.RendererFunc(func(ctx rendering.RenderContext) (string, error) {
+		calls++
+		return fmt.Sprint(len(ctx.Alternates)), nil
+	}), rendering.Cache(0))
+
+	for _, ctx := range []rendering.RenderContext{
+		{},
+		{Alternates: []i18n.Alternate{{Lang: "de", URL: "/de/"}}},
+		{Debug: &rendering.Debug{}},
+		{Debug: &rendering.Debug{}},
+	} {
+		if _, err := r.Render(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if calls != 4 {
+		t.Errorf("alternates and debug renders must not hit the cache, got %d calls", calls)
+	}
+}
+
+func TestMinify(t *testing.T) {
+	in := "<!DOCTYPE html>\n<html>\n  <head>\n    <!-- comment -->\n    <title> A </title>\n  </head>\n" +
+		"  <body>\n    <p>Hello   <b>big</b>\n    world</p>\n    <pre>  keep\n    this  </pre>\n" +
+		"    <script>\n  var a  = 1;\n</script>\n  </body>\n</html>\n"
+	r := rendering.Chain(rendering.RendererFunc(func(ctx rendering.RenderContext) (string, error) {
+		return in, nil
+	}), rendering.Minify())
+
+	out, err := r.Render(rendering.RenderContext{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "<!DOCTYPE html><html><head><title>A</title></head><body><p>Hello <b>big</b> world</p>" +
+		"<pre>  keep\n    this  </pre><script>\n  var a  = 1;\n</script></body></html>"
+	if out != want {
+		t.Errorf("got  %q\nwant %q", out, want)
+	}
+}
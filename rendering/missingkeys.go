@@ -0,0 +1,324 @@
+package rendering
+
+import (
+	"fmt"
+	"html/template"
+	"reflect"
+	"text/template/parse"
+)
+
+// maxTemplateDepth stops the walk of templates that call themselves.
+const maxTemplateDepth = 64
+
+// missingKeys walks the parse trees of tmpl against data without executing
+// them and returns every map key a field refers to that the data does not
+// have. Branches whose condition is known from the data are followed like
+// execution would; values computed by functions are unknown and not checked.
+func (r HTMLRenderer) missingKeys(tmpl *template.Template, files []string, data any) []*RenderError {
+	w := &keyWalker{tmpl: tmpl, seen: map[string]bool{}}
+	if tmpl.Tree != nil {
+		w.walkTemplate(tmpl.Tree, known(data), 0)
+	}
+
+	var warnings []*RenderError
+	for _, err := range w.errs {
+		if re, ok := newRenderError(r.FS, files, err).(*RenderError); ok {
+			warnings = append(warnings, re)
+		}
+	}
+	return warnings
+}
+
+// value is a value of the walked data. Unknown values, e.g. function
+// results, are not checked.
+type value struct {
+	v     reflect.Value
+	known bool
+}
+
+func known(v any) value {
+	return value{v: reflect.ValueOf(v), known: true}
+}
+
+type keyWalker struct {
+	tmpl *template.Template
+	errs []error
+	seen map[string]bool
+}
+
+type scope struct {
+	tree *parse.Tree
+	dot  value
+	vars map[string]value
+}
+
+func (w *keyWalker) walkTemplate(tree *parse.Tree, dot value, depth int) {
+	if depth > maxTemplateDepth || tree.Root == nil {
+		return
+	}
+	w.walk(&scope{tree: tree, dot: dot, vars: map[string]value{"$": dot}}, tree.Root, depth)
+}
+
+func (w *keyWalker) walk(s *scope, node parse.Node, depth int) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			w.walk(s, c, depth)
+		}
+	case *parse.ActionNode:
+		s.declare(n.Pipe, w.pipe(s, n.Pipe))
+	case *parse.IfNode:
+		cond := w.pipe(s, n.Pipe)
+		s.declare(n.Pipe, cond)
+		w.branch(s, cond, n.List, n.ElseList, depth)
+	case *parse.WithNode:
+		v := w.pipe(s, n.Pipe)
+		s.declare(n.Pipe, v)
+		if !v.known {
+			w.walk(s.with(value{}), n.List, depth)
+			w.walk(s, n.ElseList, depth)
+		} else if truth(v.v) {
+			w.walk(s.with(v), n.List, depth)
+		} else {
+			w.walk(s, n.ElseList, depth)
+		}
+	case *parse.RangeNode:
+		w.walkRange(s, n, depth)
+	case *parse.TemplateNode:
+		t := w.tmpl.Lookup(n.Name)
+		if t == nil || t.Tree == nil {
+			return
+		}
+		dot := value{}
+		if n.Pipe != nil {
+			dot = w.pipe(s, n.Pipe)
+		}
+		w.walkTemplate(t.Tree, dot, depth+1)
+	}
+}
+
+func (w *keyWalker) branch(s *scope, cond value, list, elseList *parse.ListNode, depth int) {
+	if !cond.known || truth(cond.v) {
+		w.walk(s, list, depth)
+	}
+	if !cond.known || !truth(cond.v) {
+		w.walk(s, elseList, depth)
+	}
+}
+
+func (w *keyWalker) walkRange(s *scope, n *parse.RangeNode, depth int) {
+	v := w.pipe(s, n.Pipe)
+	rv := indirect(v.v)
+	if !v.known || !rv.IsValid() {
+		w.walk(s.with(value{}), n.List, depth)
+		w.walk(s, n.ElseList, depth)
+		return
+	}
+
+	var elems []value
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := range rv.Len() {
+			elems = append(elems, value{v: rv.Index(i), known: true})
+		}
+	case reflect.Map:
+		for _, k := range rv.MapKeys() {
+			elems = append(elems, value{v: rv.MapIndex(k), known: true})
+		}
+	default:
+		w.walk(s.with(value{}), n.List, depth)
+		w.walk(s, n.ElseList, depth)
+		return
+	}
+
+	if len(elems) == 0 {
+		w.walk(s, n.ElseList, depth)
+		return
+	}
+	for _, e := range elems {
+		inner := s.with(e)
+		for i, decl := range n.Pipe.Decl {
+			// With two variables the first is the index or key.
+			if i == len(n.Pipe.Decl)-1 {
+				inner.vars[decl.Ident[0]] = e
+			} else {
+				inner.vars[decl.Ident[0]] = value{}
+			}
+		}
+		w.walk(inner, n.List, depth)
+	}
+}
+
+// with returns a scope with dot set to v. Variables are copied, so
+// declarations inside the block do not leak out of it.
+func (s *scope) with(v value) *scope {
+	vars := make(map[string]value, len(s.vars))
+	for k, val := range s.vars {
+		vars[k] = val
+	}
+	return &scope{tree: s.tree, dot: v, vars: vars}
+}
+
+// pipe checks the commands of a pipeline and returns its value if known.
+func (w *keyWalker) pipe(s *scope, p *parse.PipeNode) value {
+	if p == nil {
+		return value{}
+	}
+
+	var v value
+	for _, c := range p.Cmds {
+		v = w.command(s, c)
+	}
+	if len(p.Cmds) > 1 {
+		return value{}
+	}
+	return v
+}
+
+// declare sets the variables declared or assigned by p.
+func (s *scope) declare(p *parse.PipeNode, v value) {
+	for _, d := range p.Decl {
+		s.vars[d.Ident[0]] = v
+	}
+}
+
+func (w *keyWalker) command(s *scope, c *parse.CommandNode) value {
+	if len(c.Args) == 0 {
+		return value{}
+	}
+
+	if ident, ok := c.Args[0].(*parse.IdentifierNode); ok {
+		// and and or stop at the first argument that decides the result.
+		for _, arg := range c.Args[1:] {
+			v := w.arg(s, arg)
+			if v.known && (ident.Ident == "and" && !truth(v.v) || ident.Ident == "or" && truth(v.v)) {
+				break
+			}
+		}
+		return value{}
+	}
+
+	v := w.arg(s, c.Args[0])
+	for _, arg := range c.Args[1:] {
+		w.arg(s, arg)
+	}
+	if len(c.Args) > 1 {
+		return value{}
+	}
+	return v
+}
+
+func (w *keyWalker) arg(s *scope, node parse.Node) value {
+	switch n := node.(type) {
+	case *parse.DotNode:
+		return s.dot
+	case *parse.FieldNode:
+		return w.fields(s, n, s.dot, n.Ident)
+	case *parse.VariableNode:
+		v, ok := s.vars[n.Ident[0]]
+		if !ok {
+			return value{}
+		}
+		return w.fields(s, n, v, n.Ident[1:])
+	case *parse.ChainNode:
+		return w.fields(s, n, w.arg(s, n.Node), n.Field)
+	case *parse.PipeNode:
+		return w.pipe(s, n)
+	case *parse.BoolNode:
+		return known(n.True)
+	case *parse.StringNode:
+		return known(n.Text)
+	case *parse.NilNode:
+		return known(nil)
+	}
+	return value{}
+}
+
+// fields follows idents from v and records the first missing map key.
+func (w *keyWalker) fields(s *scope, node parse.Node, v value, idents []string) value {
+	for _, name := range idents {
+		if !v.known {
+			return value{}
+		}
+
+		// Methods win over fields and map keys, like in text/template.
+		rv := v.v
+		for rv.IsValid() && rv.Kind() == reflect.Interface && !rv.IsNil() {
+			rv = rv.Elem()
+		}
+		if !rv.IsValid() {
+			return value{}
+		}
+		ptr := rv
+		if ptr.Kind() != reflect.Interface && ptr.Kind() != reflect.Pointer && ptr.CanAddr() {
+			ptr = ptr.Addr()
+		}
+		if ptr.MethodByName(name).IsValid() {
+			return value{}
+		}
+		if rv = indirect(rv); !rv.IsValid() {
+			return value{}
+		}
+
+		switch rv.Kind() {
+		case reflect.Map:
+			if rv.Type().Key().Kind() != reflect.String {
+				return value{}
+			}
+			elem := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+			if !elem.IsValid() {
+				w.missing(s, node, name)
+				return value{}
+			}
+			v = value{v: elem, known: true}
+		case reflect.Struct:
+			f, ok := rv.Type().FieldByName(name)
+			if !ok || !f.IsExported() {
+				return value{}
+			}
+			v = value{v: rv.FieldByIndex(f.Index), known: true}
+		default:
+			return value{}
+		}
+	}
+	return v
+}
+
+func (w *keyWalker) missing(s *scope, node parse.Node, key string) {
+	location, context := s.tree.ErrorContext(node)
+	id := location + "\x00" + key
+	if w.seen[id] {
+		return
+	}
+	w.seen[id] = true
+	w.errs = append(w.errs, fmt.Errorf("template: %s: executing %q at <%s>: map has no entry for key %q", location, s.tree.Name, context, key))
+}
+
+// indirect unwraps interfaces and pointers; nil pointers are invalid.
+func indirect(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// truth reports whether if would take the branch for v, like text/template.
+func truth(v reflect.Value) bool {
+	v = indirect(v)
+	if !v.IsValid() {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() > 0
+	case reflect.Struct:
+		return true
+	}
+	return !v.IsZero()
+}
@@ -0,0 +1,225 @@
+package rendering
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// partialCache keeps the expanded partial file lists per FS and patterns,
+// so they are not walked and globbed on every render. An entry is dropped
+// when one of the searched directories changes, e.g. a partial is added.
+var partialCache = struct {
+	mu      sync.Mutex
+	entries map[cacheKey]partialEntry
+}{entries: make(map[cacheKey]partialEntry)}
+
+type partialEntry struct {
+	files  []string
+	dirs   []string
+	stamps []fileStamp
+}
+
+// partialFiles expands r.Partials into a sorted list of files. Entries are
+// glob patterns or directories, which are walked recursively.
+func (r HTMLRenderer) partialFiles() ([]string, error) {
+	if len(r.Partials) == 0 {
+		return nil, nil
+	}
+
+	id, ok := fsID(r.FS)
+	if !ok {
+		files, _, err := r.findPartials()
+		return files, err
+	}
+	key := cacheKey{fsys: id, files: strings.Join(r.Partials, "\x00")}
+
+	partialCache.mu.Lock()
+	entry, ok := partialCache.entries[key]
+	partialCache.mu.Unlock()
+	if ok {
+		if stamps, err := stat(r.FS, entry.dirs); err == nil && sameStamps(entry.stamps, stamps) {
+			return entry.files, nil
+		}
+	}
+
+	files, dirs, err := r.findPartials()
+	if err != nil {
+		return nil, err
+	}
+	stamps, err := stat(r.FS, dirs)
+	if err != nil {
+		return nil, err
+	}
+
+	partialCache.mu.Lock()
+	partialCache.entries[key] = partialEntry{files: files, dirs: dirs, stamps: stamps}
+	partialCache.mu.Unlock()
+	return files, nil
+}
+
+// findPartials returns the partial files and the directories they were
+// found in.
+func (r HTMLRenderer) findPartials() ([]string, []string, error) {
+	seen := make(map[string]struct{})
+	var files, dirs []string
+	add := func(name string) {
+		if _, ok := seen[name]; ok {
+			return
+		}
+		seen[name] = struct{}{}
+		files = append(files, name)
+	}
+	addDir := func(name string) {
+		if !slices.Contains(dirs, name) {
+			dirs = append(dirs, name)
+		}
+	}
+
+	for _, pattern := range r.Partials {
+		if isDir(r.FS, pattern) {
+			found, walked, err := walkFiles(r.FS, pattern)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to read partials dir %s: %w", pattern, err)
+			}
+			for _, f := range found {
+				add(f)
+			}
+			for _, d := range walked {
+				addDir(d)
+			}
+			continue
+		}
+
+		matches, err := glob(r.FS, pattern)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid partials pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			return nil, nil, fmt.Errorf("partials pattern %q matches no files", pattern)
+		}
+		for _, m := range matches {
+			if !isDir(r.FS, m) {
+				add(m)
+				addDir(dir(r.FS, m))
+			}
+		}
+	}
+
+	sort.Strings(files)
+	return files, dirs, nil
+}
+
+// checkPartials returns an error if a partial defines a template name that
+// another partial or one of the other files, i.e. layouts and the page,
+// defines as well.
+func checkPartials(partials, others []string, define definer) error {
+	defined := make(map[string]string)
+	for _, file := range partials {
+		names, err := define(file)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			if other, ok := defined[name]; ok {
+				return fmt.Errorf("template %q is defined in both %s and %s", name, other, file)
+			}
+			defined[name] = file
+		}
+	}
+	if len(defined) == 0 {
+		return nil
+	}
+
+	for _, file := range others {
+		if slices.Contains(partials, file) {
+			continue
+		}
+		// Errors in other files are left to the parser to report.
+		names, err := define(file)
+		if err != nil {
+			continue
+		}
+
+		for _, name := range names {
+			if partial, ok := defined[name]; ok {
+				return fmt.Errorf("template %q is defined in both %s and %s", name, file, partial)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isFile(fsys fs.FS, name string) bool {
+	if name == "" {
+		return false
+	}
+
+	info, err := statFile(fsys, name)
+	return err == nil && !info.IsDir()
+}
+
+func isDir(fsys fs.FS, name string) bool {
+	info, err := statFile(fsys, name)
+	return err == nil && info.IsDir()
+}
+
+func statFile(fsys fs.FS, name string) (fs.FileInfo, error) {
+	if fsys != nil {
+		return fs.Stat(fsys, name)
+	}
+	return os.Stat(name)
+}
+
+func glob(fsys fs.FS, pattern string) ([]string, error) {
+	if fsys != nil {
+		return fs.Glob(fsys, pattern)
+	}
+	return filepath.Glob(pattern)
+}
+
+func dir(fsys fs.FS, name string) string {
+	if fsys != nil {
+		return path.Dir(name)
+	}
+	return filepath.Dir(name)
+}
+
+// walkFiles returns the files below dir and the directories walked.
+func walkFiles(fsys fs.FS, dir string) ([]string, []string, error) {
+	var files, dirs []string
+	collect := func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			dirs = append(dirs, p)
+		} else if !strings.HasPrefix(d.Name(), ".") {
+			files = append(files, p)
+		}
+		return nil
+	}
+
+	var err error
+	if fsys != nil {
+		err = fs.WalkDir(fsys, dir, collect)
+	} else {
+		err = filepath.WalkDir(dir, collect)
+	}
+	return files, dirs, err
+}
+
+func readFile(fsys fs.FS, name string) ([]byte, error) {
+	if fsys != nil {
+		return fs.ReadFile(fsys, name)
+	}
+	return os.ReadFile(name)
+}
@@ -1,10 +1,54 @@
 package rendering
 
+import (
+	"io"
+
+	"github.com/janmarkuslanger/ssgo/i18n"
+	"github.com/janmarkuslanger/ssgo/toc"
+)
+
 type RenderContext struct {
 	Data     map[string]any
+	Site     map[string]any
 	Template string
+	// URL is the root-relative URL of the page including the base path.
+	URL string
+	// Canonical is the absolute URL of the page.
+	Canonical string
+	BaseURL   string
+	// LangBaseURL is the root of the page's language on multilingual sites.
+	LangBaseURL string
+	Lang        string
+	// Alternates are the page's translations for hreflang links.
+	Alternates []i18n.Alternate
+	// Translate backs the T template function.
+	Translate func(key string, args ...any) string
+	// Debug collects debug output of renderers in debug mode, if set.
+	Debug *Debug
+	// TOC receives the page's table of contents from renderers that collect
+	// headings, e.g. HTMLRenderer with Headings set, if set.
+	TOC *toc.TOC
 }
 
 type Renderer interface {
 	Render(ctx RenderContext) (output string, err error)
 }
+
+// StreamRenderer is implemented by renderers that can write their output
+// directly instead of returning it as a string.
+type StreamRenderer interface {
+	RenderTo(ctx RenderContext, w io.Writer) error
+}
+
+// TemplateStamper is implemented by renderers whose output depends on
+// template files. The stamp changes whenever one of the files a template is
+// rendered from changes, so the Cache middleware drops outdated output.
+type TemplateStamper interface {
+	TemplateStamp(name string) (string, error)
+}
+
+// TemplateChecker is implemented by renderers that can tell whether a
+// template exists before rendering.
+type TemplateChecker interface {
+	HasTemplate(name string) bool
+}
@@ -0,0 +1,66 @@
+package rendering
+
+import (
+	"bytes"
+	"html/template"
+	"path"
+	"path/filepath"
+
+	"github.com/janmarkuslanger/ssgo/shortcode"
+)
+
+var shortcodeCache = newTemplateCache[*template.Template]()
+
+// shortcodeFuncs provides the shortcodes template function, which renders
+// {{< name >}} calls in content, e.g. {{ shortcodes .body }}. An optional
+// second argument names the content in errors.
+func (r HTMLRenderer) shortcodeFuncs(ctx RenderContext) template.FuncMap {
+	return template.FuncMap{
+		"shortcodes": func(content string, source ...string) (template.HTML, error) {
+			name := "content"
+			if len(source) > 0 {
+				name = source[0]
+			}
+
+			out, err := shortcode.Render(name, content, r.lookupShortcode(ctx))
+			return template.HTML(out), err
+		},
+	}
+}
+
+// lookupShortcode prefers Go funcs over templates with the same name.
+func (r HTMLRenderer) lookupShortcode(ctx RenderContext) shortcode.Lookup {
+	return func(name string) (shortcode.Func, bool) {
+		if f, ok := r.ShortcodeFuncs[name]; ok {
+			return f, true
+		}
+
+		file, ok := r.Shortcodes[name]
+		if !ok {
+			return nil, false
+		}
+		return func(sc shortcode.Shortcode) (string, error) {
+			return r.renderShortcode(ctx, file, sc)
+		}, true
+	}
+}
+
+func (r HTMLRenderer) renderShortcode(ctx RenderContext, file string, sc shortcode.Shortcode) (string, error) {
+	tmpl, err := shortcodeCache.get(r.FS, []string{file}, r.CustomFuncs, func() (*template.Template, error) {
+		tmpl := template.New(path.Base(filepath.ToSlash(file))).Funcs(DefaultFuncs()).Funcs(contextFuncs(RenderContext{})).Funcs(r.debugFuncs(RenderContext{})).Funcs(r.CustomFuncs)
+		if r.FS != nil {
+			return tmpl.ParseFS(r.FS, file)
+		}
+		return tmpl.ParseFiles(file)
+	})
+	if err != nil {
+		return "", err
+	}
+	tmpl.Funcs(contextFuncs(ctx)).Funcs(r.debugFuncs(ctx)).Funcs(r.CustomFuncs)
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, sc); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
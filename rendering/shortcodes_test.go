@@ -0,0 +1,80 @@
+package rendering_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/janmarkuslanger/ssgo/rendering"
+	"github.com/janmarkuslanger/ssgo/shortcode"
+)
+
+func shortcodeRenderer() rendering.HTMLRenderer {
+	return rendering.HTMLRenderer{
+		FS: fstest.MapFS{
+			"page.html":               {Data: []byte(`{{ define "root" }}<article>{{ shortcodes .body "posts/hello.md" }}</article>{{ end }}`)},
+			"shortcodes/youtube.html": {Data: []byte(`<iframe src="https://www.youtube.com/embed/{{ .Get "id" }}" title="{{ .Get "title" }}"></iframe>`)},
+			"shortcodes/figure.html":  {Data: []byte(`<figure><img src="{{ relURL (.Get "src") }}">{{ with .Inner }}<figcaption>{{ . }}</figcaption>{{ end }}</figure>`)},
+		},
+		Shortcodes: map[string]string{
+			"youtube": "shortcodes/youtube.html",
+			"figure":  "shortcodes/figure.html",
+		},
+		ShortcodeFuncs: map[string]shortcode.Func{
+			"upper": func(s shortcode.Shortcode) (string, error) {
+				return strings.ToUpper(string(s.Inner)), nil
+			},
+		},
+	}
+}
+
+func TestHTMLRenderer_Render_Shortcodes(t *testing.T) {
+	body := `<p>Intro</p>
+{{< youtube id="abc" title="A & B" >}}
+{{< figure src="/img/cat.png" >}}A {{< upper >}}cat{{< /upper >}}{{< /figure >}}`
+
+	out, err := shortcodeRenderer().Render(rendering.RenderContext{
+		Template: "page.html",
+		Data:     map[string]any{"body": body},
+		BaseURL:  "https://example.com/docs/",
+	})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	want := `<article><p>Intro</p>
+<iframe src="https://www.youtube.com/embed/abc" title="A &amp; B"></iframe>
+<figure><img src="/docs/img/cat.png"><figcaption>A CAT</figcaption></figure></article>`
+	if out != want {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out, want)
+	}
+}
+
+func TestHTMLRenderer_Render_UnknownShortcode(t *testing.T) {
+	_, err := shortcodeRenderer().Render(rendering.RenderContext{
+		Template: "page.html",
+		Data:     map[string]any{"body": "text\n\n  {{< gallery >}}"},
+	})
+
+	var scErr *shortcode.Error
+	if !errors.As(err, &scErr) {
+		t.Fatalf("expected shortcode error, got %v", err)
+	}
+	if scErr.Source != "posts/hello.md" || scErr.Line != 3 || scErr.Column != 3 || scErr.Name != "gallery" {
+		t.Errorf("unexpected error: %+v", scErr)
+	}
+}
+
+func TestHTMLRenderer_Render_ShortcodesBeforeMarkdown(t *testing.T) {
+	r := shortcodeRenderer()
+	r.FS.(fstest.MapFS)["md.html"] = &fstest.MapFile{Data: []byte(`{{ define "root" }}{{ shortcodes .body | markdownify }}{{ end }}`)}
+
+	out, err := r.Render(rendering.RenderContext{Template: "md.html", Data: map[string]any{"body": "# {{< upper >}}title{{< /upper >}}"}})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if out != "<h1>TITLE</h1>\n" {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
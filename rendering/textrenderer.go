@@ -0,0 +1,83 @@
+package rendering
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"text/template"
+)
+
+// TextRenderer renders text/template files without HTML escaping, e.g. for
+// feeds, robots.txt, JSON or CSS. It uses the same layout model and template
+// functions as HTMLRenderer.
+type TextRenderer struct {
+	CustomFuncs template.FuncMap
+	Layout      []string
+	// FS loads layouts and templates from a file system such as embed.FS
+	// instead of the OS file system. Paths are then relative to FS.
+	FS fs.FS
+}
+
+func (r TextRenderer) Render(ctx RenderContext) (output string, err error) {
+	var buf bytes.Buffer
+	if err := r.RenderTo(ctx, &buf); err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
+
+// RenderTo executes the template directly into w. On error, w may already
+// hold partial output.
+func (r TextRenderer) RenderTo(ctx RenderContext, w io.Writer) error {
+	files, err := r.pageFiles(ctx.Template)
+	if err != nil {
+		return err
+	}
+
+	tmpl, err := textCache.get(r.FS, files, r.CustomFuncs, func() (*template.Template, error) {
+		// Unlike html/template, Clone would reset a "root" set to its empty
+		// top-level template, so the set is unnamed and "root" looked up.
+		tmpl := template.New("").Funcs(template.FuncMap(DefaultFuncs())).Funcs(template.FuncMap(contextFuncs(RenderContext{}))).Funcs(r.CustomFuncs)
+		if r.FS != nil {
+			return tmpl.ParseFS(r.FS, files...)
+		}
+		return tmpl.ParseFiles(files...)
+	})
+	if err != nil {
+		return newRenderError(r.FS, files, err)
+	}
+	tmpl.Funcs(template.FuncMap(contextFuncs(ctx))).Funcs(r.CustomFuncs)
+
+	if err := tmpl.ExecuteTemplate(w, "root", ctx.Data); err != nil {
+		return newRenderError(r.FS, files, err)
+	}
+
+	return nil
+}
+
+// pageFiles returns the layouts and template a page is parsed from.
+func (r TextRenderer) pageFiles(name string) ([]string, error) {
+	layouts, err := layoutFiles(r.FS, r.Layout, name)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	files = append(files, layouts...)
+	files = append(files, name)
+	return files, nil
+}
+
+// TemplateStamp changes whenever the template or one of its layouts changes.
+func (r TextRenderer) TemplateStamp(name string) (string, error) {
+	files, err := r.pageFiles(name)
+	if err != nil {
+		return "", err
+	}
+	return stampKey(r.FS, files)
+}
+
+func (r TextRenderer) HasTemplate(name string) bool {
+	return isFile(r.FS, name)
+}
@@ -0,0 +1,74 @@
+package rendering_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"text/template"
+
+	"github.com/janmarkuslanger/ssgo/rendering"
+)
+
+func TestTextRenderer_Render_NoEscaping(t *testing.T) {
+	tmp := t.TempDir()
+
+	layoutPath := filepath.Join(tmp, "feed.xml")
+	err := os.WriteFile(layoutPath, []byte(`{{ define "root" }}<?xml version="1.0"?><feed>{{ template "content" . }}</feed>{{ end }}`), 0644)
+	if err != nil {
+		t.Fatalf("could not write layout: %v", err)
+	}
+
+	templatePath := filepath.Join(tmp, "entries.xml")
+	err = os.WriteFile(templatePath, []byte(`{{ define "content" }}<title>{{ shout .title }}</title><link href="{{ absURL "/a?b=1&c=2" }}"/>{{ end }}`), 0644)
+	if err != nil {
+		t.Fatalf("could not write template: %v", err)
+	}
+
+	renderer := rendering.TextRenderer{
+		CustomFuncs: template.FuncMap{"shout": strings.ToUpper},
+		Layout:      []string{layoutPath},
+	}
+	out, err := renderer.Render(rendering.RenderContext{
+		Data:     map[string]any{"title": "Tom & Jerry"},
+		Template: templatePath,
+		BaseURL:  "https://example.com/",
+	})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+
+	want := `<?xml version="1.0"?><feed><title>TOM & JERRY</title><link href="https://example.com/a?b=1&c=2"/></feed>`
+	if out != want {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", out, want)
+	}
+}
+
+func TestTextRenderer_Render_FS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"robots.txt": {Data: []byte(`{{ define "root" }}Sitemap: {{ absURL "/sitemap.xml" }}{{ end }}`)},
+	}
+
+	renderer := rendering.TextRenderer{FS: fsys}
+	out, err := renderer.Render(rendering.RenderContext{Template: "robots.txt", BaseURL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("rendering failed: %v", err)
+	}
+	if out != "Sitemap: https://example.com/sitemap.xml" {
+		t.Errorf("unexpected output: %q", out)
+	}
+
+	if !renderer.HasTemplate("robots.txt") || renderer.HasTemplate("missing.txt") {
+		t.Error("unexpected HasTemplate result")
+	}
+}
+
+func TestTextRenderer_Render_TemplateNotFound(t *testing.T) {
+	renderer := rendering.TextRenderer{}
+
+	_, err := renderer.Render(rendering.RenderContext{Template: "does-not-exist.txt"})
+	if err == nil {
+		t.Fatal("expected error for missing template")
+	}
+}
@@ -0,0 +1,185 @@
+package rendering
+
+import (
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+	"text/template/parse"
+)
+
+// TemplateValidator is implemented by renderers that can check templates
+// before anything is rendered. It returns every problem found.
+type TemplateValidator interface {
+	ValidateTemplates(templates []string) []error
+}
+
+// definer parses a single template file and returns the template names it
+// defines.
+type definer func(file string) ([]string, error)
+
+func (r HTMLRenderer) ValidateTemplates(templates []string) []error {
+	partials, err := r.partialFiles()
+	if err != nil {
+		return []error{err}
+	}
+
+	errs := validateTemplates(r.FS, r.Layout, partials, templates, r.definitions)
+	report := func(err error) {
+		if err != nil && !slices.ContainsFunc(errs, func(e error) bool { return e.Error() == err.Error() }) {
+			errs = append(errs, err)
+		}
+	}
+
+	report(checkPartials(partials, r.Layout, r.definitions))
+	for _, file := range templates {
+		if file == "" || !isFile(r.FS, file) {
+			continue
+		}
+		if layouts, err := layoutFiles(r.FS, r.Layout, file); err == nil {
+			report(checkPartials(partials, slices.Concat(layouts, []string{file}), r.definitions))
+		}
+	}
+	return errs
+}
+
+func (r TextRenderer) ValidateTemplates(templates []string) []error {
+	return validateTemplates(r.FS, r.Layout, nil, templates, r.definitions)
+}
+
+// definitions parses a file with all template functions, so references to
+// unknown functions are reported as well.
+func (r HTMLRenderer) definitions(file string) ([]string, error) {
+	content, err := readFile(r.FS, file)
+	if err != nil {
+		return nil, err
+	}
+
+	base := path.Base(filepath.ToSlash(file))
+	tmpl := template.New(base).Funcs(DefaultFuncs()).Funcs(contextFuncs(RenderContext{})).Funcs(r.shortcodeFuncs(RenderContext{})).Funcs(r.debugFuncs(RenderContext{})).Funcs(r.CustomFuncs)
+	if _, err := tmpl.Parse(string(content)); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	var trees []*parse.Tree
+	for _, t := range tmpl.Templates() {
+		trees = append(trees, t.Tree)
+	}
+	return definedNames(base, trees), nil
+}
+
+func (r TextRenderer) definitions(file string) ([]string, error) {
+	content, err := readFile(r.FS, file)
+	if err != nil {
+		return nil, err
+	}
+
+	base := path.Base(filepath.ToSlash(file))
+	tmpl := texttemplate.New(base).Funcs(texttemplate.FuncMap(DefaultFuncs())).Funcs(texttemplate.FuncMap(contextFuncs(RenderContext{}))).Funcs(r.CustomFuncs)
+	if _, err := tmpl.Parse(string(content)); err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+
+	var trees []*parse.Tree
+	for _, t := range tmpl.Templates() {
+		trees = append(trees, t.Tree)
+	}
+	return definedNames(base, trees), nil
+}
+
+// definedNames skips the file's own template when it only holds define
+// blocks, as ParseFiles registers every file under its base name.
+func definedNames(base string, trees []*parse.Tree) []string {
+	var names []string
+	for _, t := range trees {
+		if t == nil || (t.Name == base && parse.IsEmptyTree(t.Root)) {
+			continue
+		}
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// validateTemplates checks the HTMLRenderer contract: layouts define "root"
+// and content templates define "content". Without layouts, a template has
+// to define "root" itself. Layouts picked with extends are checked per
+// template.
+func validateTemplates(fsys fs.FS, layouts, partials, templates []string, define definer) []error {
+	var errs []error
+	report := func(err error) {
+		if !slices.ContainsFunc(errs, func(e error) bool { return e.Error() == err.Error() }) {
+			errs = append(errs, err)
+		}
+	}
+
+	defined := make(map[string][]string)
+	parseFile := func(file string) ([]string, bool) {
+		if names, ok := defined[file]; ok {
+			return names, true
+		}
+		names, err := define(file)
+		if err != nil {
+			report(err)
+			return nil, false
+		}
+		defined[file] = names
+		return names, true
+	}
+
+	for _, file := range partials {
+		parseFile(file)
+	}
+
+	checkLayouts := func(files []string) {
+		root, ok := false, true
+		for _, file := range files {
+			names, parsed := parseFile(file)
+			ok = ok && parsed
+			root = root || slices.Contains(names, "root")
+		}
+		if len(files) > 0 && ok && !root {
+			report(fmt.Errorf("layouts %s do not define %q", strings.Join(files, ", "), "root"))
+		}
+	}
+	checkLayouts(layouts)
+
+	seen := make(map[string]bool)
+	for _, file := range templates {
+		if file == "" || seen[file] {
+			continue
+		}
+		seen[file] = true
+
+		chain, err := layoutFiles(fsys, layouts, file)
+		if err != nil {
+			report(err)
+			continue
+		}
+		checkLayouts(chain)
+
+		names, ok := parseFile(file)
+		if !ok {
+			continue
+		}
+
+		// A content block may also come from a layout, e.g. a section layout
+		// that the template extends.
+		want := "content"
+		if len(chain) == 0 {
+			want = "root"
+		}
+		names = slices.Clone(names)
+		for _, layout := range chain {
+			names = append(names, defined[layout]...)
+		}
+		if !slices.Contains(names, want) {
+			report(fmt.Errorf("%s does not define %q", file, want))
+		}
+	}
+
+	return errs
+}
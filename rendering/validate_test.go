@@ -0,0 +1,47 @@
+package rendering_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/janmarkuslanger/ssgo/rendering"
+)
+
+func TestHTMLRenderer_ValidateTemplates(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layout.html":        {Data: []byte(`{{ define "root" }}{{ template "content" . }}{{ end }}`)},
+		"partials/a.html":    {Data: []byte(`{{ define "card" }}{{ end }}`)},
+		"partials/b.html":    {Data: []byte(`{{ define "card" }}{{ end }}`)},
+		"templates/ok.html":  {Data: []byte(`{{ define "content" }}{{ slugify .title }}{{ end }}`)},
+		"templates/bad.html": {Data: []byte(`{{ define "content" }}{{ if }}{{ end }}`)},
+	}
+
+	r := rendering.HTMLRenderer{FS: fsys, Layout: []string{"layout.html"}, Partials: []string{"partials"}}
+	errs := r.ValidateTemplates([]string{"templates/ok.html", "templates/bad.html", "templates/ok.html"})
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 problems, got %v", errs)
+	}
+	if !strings.HasPrefix(errs[0].Error(), "templates/bad.html: ") {
+		t.Errorf("unexpected parse error: %v", errs[0])
+	}
+	if !strings.Contains(errs[1].Error(), `template "card" is defined in both`) {
+		t.Errorf("unexpected duplicate error: %v", errs[1])
+	}
+
+	if errs := (rendering.HTMLRenderer{FS: fsys, Layout: []string{"layout.html"}}).ValidateTemplates([]string{"templates/ok.html"}); len(errs) != 0 {
+		t.Errorf("expected valid templates, got %v", errs)
+	}
+}
+
+func TestTextRenderer_ValidateTemplates_WithoutLayout(t *testing.T) {
+	fsys := fstest.MapFS{
+		"robots.txt":  {Data: []byte(`{{ define "root" }}User-agent: *{{ end }}`)},
+		"sitemap.xml": {Data: []byte(`{{ define "content" }}{{ end }}`)},
+	}
+
+	errs := rendering.TextRenderer{FS: fsys}.ValidateTemplates([]string{"robots.txt", "sitemap.xml"})
+	if len(errs) != 1 || errs[0].Error() != `sitemap.xml does not define "root"` {
+		t.Errorf("unexpected problems: %v", errs)
+	}
+}
@@ -0,0 +1,215 @@
+package shortcode
+
+import (
+	"fmt"
+	"html/template"
+	"regexp"
+	"strings"
+)
+
+// Shortcode is one {{< name ... >}} call in content.
+type Shortcode struct {
+	Name string
+	// Params holds key="value" arguments.
+	Params map[string]string
+	// Args holds positional arguments in order.
+	Args []string
+	// Inner is the rendered content between the opening and closing tag.
+	Inner template.HTML
+	// Line and Column locate the opening tag in the content, 1-based.
+	Line   int
+	Column int
+}
+
+// Get returns a named parameter, e.g. {{ .Get "id" }} in a shortcode template.
+func (s Shortcode) Get(key string) string {
+	return s.Params[key]
+}
+
+// Func renders a shortcode to HTML.
+type Func func(s Shortcode) (string, error)
+
+// Lookup returns the renderer for a shortcode name.
+type Lookup func(name string) (Func, bool)
+
+// Error points to the shortcode in the content that failed.
+type Error struct {
+	Source string
+	Line   int
+	Column int
+	Name   string
+	Err    error
+}
+
+func (e *Error) Error() string {
+	loc := fmt.Sprintf("%s:%d:%d", e.Source, e.Line, e.Column)
+	if e.Name == "" {
+		return loc + ": " + e.Err.Error()
+	}
+	return fmt.Sprintf("%s: shortcode %q: %v", loc, e.Name, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+var (
+	tagPattern   = regexp.MustCompile(`\{\{<\s*(/?)\s*([\w-]+)((?:[^>]|>[^}])*?)\s*(/?)>\}\}`)
+	paramPattern = regexp.MustCompile(`([\w-]+)=(?:"((?:[^"\\]|\\.)*)"|(\S+))|"((?:[^"\\]|\\.)*)"|(\S+)`)
+)
+
+type tag struct {
+	start, end int
+	closing    bool
+	selfClose  bool
+	sc         Shortcode
+	match      int // index of the closing tag, -1 if standalone
+}
+
+// Render replaces all shortcodes in content. Shortcodes with a matching
+// closing tag receive the rendered content in between as Inner; others are
+// standalone. source names the content in errors, e.g. a file or data key.
+func Render(source, content string, lookup Lookup) (string, error) {
+	tags, err := parse(source, content)
+	if err != nil {
+		return "", err
+	}
+
+	out, _, err := render(source, content, tags, 0, len(tags), 0, len(content), lookup)
+	return out, err
+}
+
+func parse(source, content string) ([]tag, error) {
+	var tags []tag
+	for _, m := range tagPattern.FindAllStringSubmatchIndex(content, -1) {
+		line, col := position(content, m[0])
+		t := tag{
+			start:     m[0],
+			end:       m[1],
+			closing:   m[3] > m[2],
+			selfClose: m[9] > m[8],
+			match:     -1,
+			sc: Shortcode{
+				Name:   content[m[4]:m[5]],
+				Params: map[string]string{},
+				Line:   line,
+				Column: col,
+			},
+		}
+		if !t.closing {
+			params(&t.sc, content[m[6]:m[7]])
+		}
+		tags = append(tags, t)
+	}
+
+	if i := unterminated(content, tags); i >= 0 {
+		line, col := position(content, i)
+		return nil, &Error{Source: source, Line: line, Column: col, Err: fmt.Errorf("unterminated shortcode, missing >}}")}
+	}
+
+	// Pair closing tags with the nearest open tag of the same name. Open
+	// tags skipped over stay standalone.
+	var stack []int
+	for i, t := range tags {
+		switch {
+		case t.selfClose:
+		case !t.closing:
+			stack = append(stack, i)
+		default:
+			j := len(stack) - 1
+			for j >= 0 && tags[stack[j]].sc.Name != t.sc.Name {
+				j--
+			}
+			if j < 0 {
+				return nil, &Error{Source: source, Line: t.sc.Line, Column: t.sc.Column, Name: t.sc.Name, Err: fmt.Errorf("closing tag without opening tag")}
+			}
+			tags[stack[j]].match = i
+			stack = stack[:j]
+		}
+	}
+
+	return tags, nil
+}
+
+// render writes content[from:to] with the tags in [first, last), returning
+// the index of the first tag after the range.
+func render(source, content string, tags []tag, first, last, from, to int, lookup Lookup) (string, int, error) {
+	var b strings.Builder
+	pos := from
+	i := first
+	for i < last {
+		t := tags[i]
+		b.WriteString(content[pos:t.start])
+
+		f, ok := lookup(t.sc.Name)
+		if !ok {
+			return "", 0, &Error{Source: source, Line: t.sc.Line, Column: t.sc.Column, Name: t.sc.Name, Err: fmt.Errorf("unknown shortcode")}
+		}
+
+		sc := t.sc
+		next := i + 1
+		pos = t.end
+		if t.match >= 0 {
+			inner, _, err := render(source, content, tags, i+1, t.match, t.end, tags[t.match].start, lookup)
+			if err != nil {
+				return "", 0, err
+			}
+			sc.Inner = template.HTML(inner)
+			next = t.match + 1
+			pos = tags[t.match].end
+		}
+
+		out, err := f(sc)
+		if err != nil {
+			return "", 0, &Error{Source: source, Line: sc.Line, Column: sc.Column, Name: sc.Name, Err: err}
+		}
+		b.WriteString(out)
+		i = next
+	}
+	b.WriteString(content[pos:to])
+
+	return b.String(), i, nil
+}
+
+func params(sc *Shortcode, s string) {
+	for _, m := range paramPattern.FindAllStringSubmatch(s, -1) {
+		switch {
+		case m[1] != "":
+			value := m[3]
+			if m[3] == "" {
+				value = unquote(m[2])
+			}
+			sc.Params[m[1]] = value
+		case m[5] != "":
+			sc.Args = append(sc.Args, m[5])
+		default:
+			sc.Args = append(sc.Args, unquote(m[4]))
+		}
+	}
+}
+
+func unquote(s string) string {
+	return strings.NewReplacer(`\"`, `"`, `\\`, `\`).Replace(s)
+}
+
+// unterminated returns the offset of a {{< that is not part of a tag, or -1.
+func unterminated(content string, tags []tag) int {
+	pos := 0
+	for _, t := range tags {
+		if i := strings.Index(content[pos:t.start], "{{<"); i >= 0 {
+			return pos + i
+		}
+		pos = t.end
+	}
+	if i := strings.Index(content[pos:], "{{<"); i >= 0 {
+		return pos + i
+	}
+	return -1
+}
+
+func position(content string, offset int) (line, column int) {
+	before := content[:offset]
+	line = strings.Count(before, "\n") + 1
+	column = offset - strings.LastIndex(before, "\n")
+	return line, column
+}
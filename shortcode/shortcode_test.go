@@ -0,0 +1,87 @@
+package shortcode_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/janmarkuslanger/ssgo/shortcode"
+)
+
+func lookup(funcs map[string]shortcode.Func) shortcode.Lookup {
+	return func(name string) (shortcode.Func, bool) {
+		f, ok := funcs[name]
+		return f, ok
+	}
+}
+
+var testFuncs = map[string]shortcode.Func{
+	"youtube": func(s shortcode.Shortcode) (string, error) {
+		return fmt.Sprintf(`<iframe src="https://www.youtube.com/embed/%s"></iframe>`, s.Get("id")), nil
+	},
+	"note": func(s shortcode.Shortcode) (string, error) {
+		return fmt.Sprintf(`<div class="note %s">%s</div>`, s.Get("type"), s.Inner), nil
+	},
+	"args": func(s shortcode.Shortcode) (string, error) {
+		return strings.Join(s.Args, "|"), nil
+	},
+	"fail": func(s shortcode.Shortcode) (string, error) {
+		return "", errors.New("boom")
+	},
+}
+
+func TestRender(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{name: "no shortcodes", content: "<p>plain {{ text }}</p>", want: "<p>plain {{ text }}</p>"},
+		{name: "standalone", content: `a {{< youtube id="abc" >}} b`, want: `a <iframe src="https://www.youtube.com/embed/abc"></iframe> b`},
+		{name: "self closing", content: `{{< youtube id=xyz />}}`, want: `<iframe src="https://www.youtube.com/embed/xyz"></iframe>`},
+		{name: "inner", content: `{{< note type="tip" >}}Hi{{< /note >}}`, want: `<div class="note tip">Hi</div>`},
+		{
+			name:    "nested",
+			content: `{{< note type="a" >}}x {{< note type="b" >}}{{< youtube id="v" >}}{{< /note >}} y{{< /note >}}`,
+			want:    `<div class="note a">x <div class="note b"><iframe src="https://www.youtube.com/embed/v"></iframe></div> y</div>`,
+		},
+		{name: "positional", content: `{{< args one "two words" three >}}`, want: "one|two words|three"},
+		{name: "quoted", content: `{{< note type="a \"b\" > c" >}}{{< /note >}}`, want: `<div class="note a "b" > c"></div>`},
+	}
+
+	for _, c := range cases {
+		got, err := shortcode.Render("post.md", c.content, lookup(testFuncs))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.name, err)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("%s: got %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRender_Errors(t *testing.T) {
+	cases := []struct {
+		content string
+		want    string
+	}{
+		{content: "line\n  {{< missing >}}", want: `post.md:2:3: shortcode "missing": unknown shortcode`},
+		{content: "{{< /note >}}", want: `post.md:1:1: shortcode "note": closing tag without opening tag`},
+		{content: "ok\n{{< youtube id=\"a\"", want: "post.md:2:1: unterminated shortcode, missing >}}"},
+		{content: "{{< note >}}\n {{< fail >}}{{< /note >}}", want: `post.md:2:2: shortcode "fail": boom`},
+	}
+
+	for _, c := range cases {
+		_, err := shortcode.Render("post.md", c.content, lookup(testFuncs))
+		var scErr *shortcode.Error
+		if !errors.As(err, &scErr) {
+			t.Errorf("%q: expected shortcode error, got %v", c.content, err)
+			continue
+		}
+		if err.Error() != c.want {
+			t.Errorf("%q: got %q, want %q", c.content, err.Error(), c.want)
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package summary
+
+import (
+	"html/template"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Summary describes the content of a page for listings.
+type Summary struct {
+	// HTML is the content up to the <!--more--> marker or its first words,
+	// with all open tags closed.
+	HTML template.HTML
+	// Truncated reports whether HTML is shorter than the content.
+	Truncated bool
+	WordCount int
+	// ReadingTime is in minutes, at least 1 for content with words.
+	ReadingTime int
+}
+
+type Options struct {
+	// Key is the data key holding the page's HTML content; "content" by default.
+	Key string
+	// Words is the summary length without a <!--more--> marker; 70 by default.
+	Words int
+	// WordsPerMinute is the reading speed; 200 by default.
+	WordsPerMinute int
+}
+
+func (o Options) withDefaults() Options {
+	if o.Key == "" {
+		o.Key = "content"
+	}
+	if o.Words <= 0 {
+		o.Words = 70
+	}
+	if o.WordsPerMinute <= 0 {
+		o.WordsPerMinute = 200
+	}
+	return o
+}
+
+var (
+	morePattern = regexp.MustCompile(`<!--\s*more\s*-->`)
+	tagPattern  = regexp.MustCompile(`<!--[\s\S]*?-->|<[^>]*>`)
+	namePattern = regexp.MustCompile(`^<(/?)([a-zA-Z][\w-]*)`)
+)
+
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// New summarises HTML content.
+func New(content string, opts Options) Summary {
+	opts = opts.withDefaults()
+
+	s := Summary{WordCount: CountWords(content)}
+	s.ReadingTime = ReadingTime(s.WordCount, opts.WordsPerMinute)
+
+	if loc := morePattern.FindStringIndex(content); loc != nil {
+		s.HTML = template.HTML(strings.TrimSpace(cut(content, loc[0])))
+		s.Truncated = strings.TrimSpace(content[loc[1]:]) != ""
+		return s
+	}
+
+	out, truncated := Truncate(content, opts.Words)
+	s.HTML = template.HTML(out)
+	s.Truncated = truncated
+	return s
+}
+
+// Apply adds Summary, Truncated, WordCount and ReadingTime to data, based on
+// the content under opts.Key. Keys already present are kept.
+func Apply(data map[string]any, opts Options) {
+	opts = opts.withDefaults()
+	content, ok := data[opts.Key]
+	if !ok {
+		return
+	}
+
+	var text string
+	switch c := content.(type) {
+	case string:
+		text = c
+	case template.HTML:
+		text = string(c)
+	default:
+		return
+	}
+
+	s := New(text, opts)
+	for key, value := range map[string]any{
+		"Summary":     s.HTML,
+		"Truncated":   s.Truncated,
+		"WordCount":   s.WordCount,
+		"ReadingTime": s.ReadingTime,
+	} {
+		if _, exists := data[key]; !exists {
+			data[key] = value
+		}
+	}
+}
+
+// Truncate shortens HTML content to its first n words and closes all tags
+// left open, reporting whether anything was cut.
+func Truncate(content string, n int) (string, bool) {
+	count := 0
+	end := 0
+
+	truncated := walk(content, func(text string, offset int) bool {
+		for _, w := range wordOffsets(text) {
+			if count >= n {
+				return true
+			}
+			count++
+			if count == n {
+				end = offset + w[1]
+			}
+		}
+		return false
+	})
+	if !truncated {
+		return content, false
+	}
+
+	return cut(content, end), true
+}
+
+// CountWords counts the words in the text of HTML content, ignoring tags,
+// comments, scripts and styles.
+func CountWords(content string) int {
+	count := 0
+	walk(content, func(text string, offset int) bool {
+		count += len(wordOffsets(text))
+		return false
+	})
+	return count
+}
+
+// ReadingTime returns the minutes needed to read words at wpm words per
+// minute, rounded up.
+func ReadingTime(words, wpm int) int {
+	if words == 0 {
+		return 0
+	}
+	if wpm <= 0 {
+		wpm = 200
+	}
+	return int(math.Ceil(float64(words) / float64(wpm)))
+}
+
+// walk calls fn with every text node outside of script and style elements
+// and its offset in content, until fn returns true.
+func walk(content string, fn func(text string, offset int) bool) bool {
+	var stack []string
+	pos := 0
+
+	visit := func(end int) bool {
+		if end <= pos || inRaw(stack) {
+			return false
+		}
+		return fn(content[pos:end], pos)
+	}
+
+	for _, loc := range tagPattern.FindAllStringIndex(content, -1) {
+		if visit(loc[0]) {
+			return true
+		}
+		stack = updateStack(stack, content[loc[0]:loc[1]])
+		pos = loc[1]
+	}
+	return visit(len(content))
+}
+
+// cut returns content up to offset with the elements still open there closed.
+func cut(content string, offset int) string {
+	var stack []string
+	for _, loc := range tagPattern.FindAllStringIndex(content[:offset], -1) {
+		if loc[1] > offset {
+			break
+		}
+		stack = updateStack(stack, content[loc[0]:loc[1]])
+	}
+
+	var b strings.Builder
+	b.WriteString(content[:offset])
+	for i := len(stack) - 1; i >= 0; i-- {
+		b.WriteString("</" + stack[i] + ">")
+	}
+	return b.String()
+}
+
+func updateStack(stack []string, tag string) []string {
+	m := namePattern.FindStringSubmatch(tag)
+	if m == nil {
+		return stack
+	}
+
+	name := strings.ToLower(m[2])
+	if m[1] == "" {
+		if voidElements[name] || strings.HasSuffix(tag, "/>") {
+			return stack
+		}
+		return append(stack, name)
+	}
+
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i] == name {
+			return stack[:i]
+		}
+	}
+	return stack
+}
+
+func inRaw(stack []string) bool {
+	return len(stack) > 0 && (stack[len(stack)-1] == "script" || stack[len(stack)-1] == "style")
+}
+
+// wordOffsets returns the start and end offsets of the words in text.
+func wordOffsets(text string) [][2]int {
+	var words [][2]int
+	start := -1
+	for i, r := range text {
+		if unicode.IsSpace(r) {
+			if start >= 0 {
+				words = append(words, [2]int{start, i})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, [2]int{start, len(text)})
+	}
+	return words
+}
@@ -0,0 +1,81 @@
+package summary_test
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/janmarkuslanger/ssgo/summary"
+)
+
+func TestTruncate(t *testing.T) {
+	cases := []struct {
+		name      string
+		content   string
+		n         int
+		want      string
+		truncated bool
+	}{
+		{name: "short", content: "<p>one two</p>", n: 5, want: "<p>one two</p>"},
+		{name: "exact", content: "<p>one two</p>", n: 2, want: "<p>one two</p>"},
+		{name: "closes tags", content: "<div><p>one <em>two three</em></p><p>four</p></div>", n: 2, want: "<div><p>one <em>two</em></p></div>", truncated: true},
+		{name: "cuts before next element", content: "<p>one two</p><p>three</p>", n: 2, want: "<p>one two</p>", truncated: true},
+		{name: "void elements", content: "<p>one<br>two <img src=x.png/> three</p>", n: 2, want: "<p>one<br>two</p>", truncated: true},
+		{name: "ignores scripts", content: "<script>var a = 1;</script><p>one two three</p>", n: 2, want: "<script>var a = 1;</script><p>one two</p>", truncated: true},
+		{name: "zero words", content: "<p>one</p>", n: 0, want: "", truncated: true},
+	}
+
+	for _, c := range cases {
+		got, truncated := summary.Truncate(c.content, c.n)
+		if got != c.want || truncated != c.truncated {
+			t.Errorf("%s: got %q, %v; want %q, %v", c.name, got, truncated, c.want, c.truncated)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	content := "<p>Intro text</p>\n<!-- more -->\n<p>" + strings.Repeat("word ", 398) + "</p>"
+
+	s := summary.New(content, summary.Options{})
+	if s.HTML != template.HTML("<p>Intro text</p>") || !s.Truncated {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+	if s.WordCount != 400 || s.ReadingTime != 2 {
+		t.Errorf("got %d words, %d minutes", s.WordCount, s.ReadingTime)
+	}
+
+	s = summary.New("<p>a b c</p>", summary.Options{Words: 2, WordsPerMinute: 1})
+	if s.HTML != template.HTML("<p>a b</p>") || !s.Truncated || s.ReadingTime != 3 {
+		t.Errorf("unexpected summary: %+v", s)
+	}
+
+	// The marker inside an element closes it.
+	s = summary.New("<div><p>a</p><!--more--><p>b</p></div>", summary.Options{})
+	if s.HTML != template.HTML("<div><p>a</p></div>") {
+		t.Errorf("unexpected summary: %q", s.HTML)
+	}
+}
+
+func TestCountWords(t *testing.T) {
+	if got := summary.CountWords("<h1>A title</h1><!-- a comment --><style>p { x: y }</style><p>and&nbsp;text</p>"); got != 3 {
+		t.Errorf("got %d", got)
+	}
+	if got := summary.ReadingTime(0, 200); got != 0 {
+		t.Errorf("got %d", got)
+	}
+}
+
+func TestApply(t *testing.T) {
+	data := map[string]any{"body": template.HTML("<p>a b</p>")}
+	summary.Apply(data, summary.Options{Key: "body"})
+
+	if data["WordCount"] != 2 || data["Truncated"] != false || data["Summary"] != template.HTML("<p>a b</p>") {
+		t.Errorf("unexpected data: %v", data)
+	}
+
+	empty := map[string]any{}
+	summary.Apply(empty, summary.Options{})
+	if len(empty) != 0 {
+		t.Errorf("data without content should not change: %v", empty)
+	}
+}
@@ -3,8 +3,10 @@ package taskutil
 import (
 	"fmt"
 	"io"
+	"io/fs"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/janmarkuslanger/ssgo/task"
 )
@@ -21,6 +23,16 @@ func NewCopyTask(sourceDir string, outputSubDir string, pathResolver PathResolve
 	}
 }
 
+// NewFSCopyTask copies sourceDir from fsys, e.g. an embed.FS, into the output.
+// Use "." to copy the whole file system.
+func NewFSCopyTask(fsys fs.FS, sourceDir string, outputSubDir string) *CopyTask {
+	return &CopyTask{
+		SourceDir:    sourceDir,
+		OutputSubDir: outputSubDir,
+		FS:           fsys,
+	}
+}
+
 type PathResolver interface {
 	Abs(path string) (string, error)
 	Rel(basepath string, targpath string) (string, error)
@@ -39,23 +51,34 @@ func (p defaultPathResolver) Rel(basepath string, targpath string) (string, erro
 type CopyTask struct {
 	SourceDir    string
 	OutputSubDir string
+	// FS is read instead of the OS file system when set. SourceDir is then a
+	// slash-separated path within FS.
+	FS           fs.FS
 	pathResolver PathResolver
 }
 
 func (c *CopyTask) Run(ctx task.TaskContext) error {
-	if c.pathResolver == nil {
+	if c.FS == nil && c.pathResolver == nil {
 		return fmt.Errorf("pathresolver not defined")
 	}
 
+	outDir := ctx.OutputDir
+	if c.OutputSubDir != "" {
+		outDir = filepath.Join(outDir, c.OutputSubDir)
+	}
+
+	if c.FS != nil {
+		if err := os.MkdirAll(outDir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		return c.copyFS(outDir)
+	}
+
 	srcDirAbs, err := c.pathResolver.Abs(c.SourceDir)
 	if err != nil {
 		return fmt.Errorf("failed to resolve source dir: %w", err)
 	}
 
-	outDir := ctx.OutputDir
-	if c.OutputSubDir != "" {
-		outDir = filepath.Join(outDir, c.OutputSubDir)
-	}
 	if err := os.MkdirAll(outDir, 0755); err != nil {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
@@ -79,6 +102,51 @@ func (c *CopyTask) Run(ctx task.TaskContext) error {
 	})
 }
 
+func (c *CopyTask) copyFS(outDir string) error {
+	srcDir := c.SourceDir
+	if srcDir == "" {
+		srcDir = "."
+	}
+
+	return fs.WalkDir(c.FS, srcDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("walk error: %w", err)
+		}
+
+		relPath := p
+		if srcDir != "." {
+			relPath = strings.TrimPrefix(strings.TrimPrefix(p, srcDir), "/")
+		}
+		targetPath := filepath.Join(outDir, filepath.FromSlash(relPath))
+
+		if d.IsDir() {
+			return os.MkdirAll(targetPath, 0755)
+		}
+
+		return copyFSFile(c.FS, p, targetPath)
+	})
+}
+
+func copyFSFile(fsys fs.FS, src, dest string) error {
+	srcFile, err := fsys.Open(src)
+	if err != nil {
+		return fmt.Errorf("open src error: %w", err)
+	}
+	defer srcFile.Close()
+
+	destFile, err := os.Create(dest)
+	if err != nil {
+		return fmt.Errorf("create dest error: %w", err)
+	}
+	defer destFile.Close()
+
+	if _, err := io.Copy(destFile, srcFile); err != nil {
+		return fmt.Errorf("copy error: %w", err)
+	}
+
+	return nil
+}
+
 func (c *CopyTask) IsCritical() bool {
 	return false
 }
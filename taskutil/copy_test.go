@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/janmarkuslanger/ssgo/task"
 	"github.com/janmarkuslanger/ssgo/taskutil"
@@ -335,3 +336,45 @@ func TestCopyFile_CopyFails(t *testing.T) {
 		t.Errorf("expected copy error, got: %v", err)
 	}
 }
+
+func TestCopyTask_Run_FS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"assets/style.css":    {Data: []byte("body{}")},
+		"assets/img/logo.svg": {Data: []byte("<svg/>")},
+		"other.txt":           {Data: []byte("skip")},
+	}
+	outDir := t.TempDir()
+
+	ct := taskutil.NewFSCopyTask(fsys, "assets", "static")
+	if err := ct.Run(task.TaskContext{OutputDir: outDir}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := os.ReadFile(filepath.Join(outDir, "static", "img", "logo.svg"))
+	if err != nil || string(got) != "<svg/>" {
+		t.Fatalf("unexpected content %q, err %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(outDir, "static", "other.txt")); !os.IsNotExist(err) {
+		t.Error("files outside the source dir should not be copied")
+	}
+}
+
+func TestCopyTask_Run_FSRoot(t *testing.T) {
+	fsys := fstest.MapFS{"robots.txt": {Data: []byte("User-agent: *")}}
+	outDir := t.TempDir()
+
+	if err := taskutil.NewFSCopyTask(fsys, ".", "").Run(task.TaskContext{OutputDir: outDir}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(outDir, "robots.txt")); err != nil {
+		t.Errorf("expected robots.txt to be copied: %v", err)
+	}
+}
+
+func TestCopyTask_Run_FSMissingDir(t *testing.T) {
+	err := taskutil.NewFSCopyTask(fstest.MapFS{}, "missing", "").Run(task.TaskContext{OutputDir: t.TempDir()})
+	if err == nil || !strings.Contains(err.Error(), "walk error") {
+		t.Fatalf("expected walk error, got %v", err)
+	}
+}
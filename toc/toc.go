@@ -0,0 +1,163 @@
+package toc
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/janmarkuslanger/ssgo/urls"
+)
+
+// Heading is one entry of a table of contents.
+type Heading struct {
+	Level int
+	ID    string
+	// Text is the heading content without tags.
+	Text     string
+	Children []*Heading
+}
+
+// TOC is the nested table of contents of a page.
+type TOC []*Heading
+
+// HTML renders the table of contents as nested lists of links.
+func (t TOC) HTML() template.HTML {
+	if len(t) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	t.write(&b)
+	return template.HTML(b.String())
+}
+
+func (t TOC) write(b *strings.Builder) {
+	b.WriteString("<ul>")
+	for _, h := range t {
+		fmt.Fprintf(b, `<li><a href="#%s">%s</a>`, html.EscapeString(h.ID), html.EscapeString(h.Text))
+		if len(h.Children) > 0 {
+			TOC(h.Children).write(b)
+		}
+		b.WriteString("</li>")
+	}
+	b.WriteString("</ul>")
+}
+
+type Options struct {
+	// MinLevel and MaxLevel limit the headings listed in the TOC. They
+	// default to 2 and 3; ids are assigned to all headings.
+	MinLevel int
+	MaxLevel int
+	// AnchorLinks appends a link to the heading's own id to every heading.
+	AnchorLinks bool
+	// AnchorClass and AnchorText default to "anchor" and "#".
+	AnchorClass string
+	AnchorText  string
+}
+
+func (o Options) withDefaults() Options {
+	if o.MinLevel == 0 {
+		o.MinLevel = 2
+	}
+	if o.MaxLevel == 0 {
+		o.MaxLevel = 3
+	}
+	if o.AnchorClass == "" {
+		o.AnchorClass = "anchor"
+	}
+	if o.AnchorText == "" {
+		o.AnchorText = "#"
+	}
+	return o
+}
+
+var (
+	headingPattern = regexp.MustCompile(`(?is)<h([1-6])(\s[^>]*)?>(.*?)</h[1-6]\s*>`)
+	idPattern      = regexp.MustCompile(`(?i)\sid\s*=\s*(?:"([^"]*)"|'([^']*)')`)
+	tagPattern     = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// Process gives every h1–h6 in content a unique id derived from its text,
+// adds anchor links if enabled, and returns the updated HTML with its table
+// of contents. Existing ids are kept.
+func Process(content string, opts Options) (string, TOC) {
+	opts = opts.withDefaults()
+
+	used := make(map[string]bool)
+	for _, m := range headingPattern.FindAllStringSubmatch(content, -1) {
+		if id := existingID(m[2]); id != "" {
+			used[id] = true
+		}
+	}
+
+	var toc TOC
+	var stack []*Heading
+
+	out := headingPattern.ReplaceAllStringFunc(content, func(tag string) string {
+		m := headingPattern.FindStringSubmatch(tag)
+		level, _ := strconv.Atoi(m[1])
+		attrs, inner := m[2], m[3]
+
+		h := &Heading{Level: level, Text: text(inner), ID: existingID(attrs)}
+		if h.ID == "" {
+			h.ID = unique(urls.Slugify(h.Text), used)
+			attrs += ` id="` + html.EscapeString(h.ID) + `"`
+		}
+
+		if opts.AnchorLinks && !strings.Contains(inner, `href="#`+html.EscapeString(h.ID)+`"`) {
+			inner += fmt.Sprintf(` <a class="%s" href="#%s" aria-hidden="true">%s</a>`,
+				html.EscapeString(opts.AnchorClass), html.EscapeString(h.ID), html.EscapeString(opts.AnchorText))
+		}
+
+		if level >= opts.MinLevel && level <= opts.MaxLevel {
+			for len(stack) > 0 && stack[len(stack)-1].Level >= level {
+				stack = stack[:len(stack)-1]
+			}
+			if len(stack) == 0 {
+				toc = append(toc, h)
+			} else {
+				parent := stack[len(stack)-1]
+				parent.Children = append(parent.Children, h)
+			}
+			stack = append(stack, h)
+		}
+
+		return "<h" + m[1] + attrs + ">" + inner + "</h" + m[1] + ">"
+	})
+
+	return out, toc
+}
+
+// Extract returns the table of contents of content without changing it.
+func Extract(content string, opts Options) TOC {
+	_, toc := Process(content, opts)
+	return toc
+}
+
+func existingID(attrs string) string {
+	m := idPattern.FindStringSubmatch(attrs)
+	if m == nil {
+		return ""
+	}
+	return html.UnescapeString(m[1] + m[2])
+}
+
+func text(inner string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(tagPattern.ReplaceAllString(inner, ""))), " ")
+}
+
+func unique(id string, used map[string]bool) string {
+	if id == "" {
+		id = "section"
+	}
+
+	candidate := id
+	for i := 1; used[candidate]; i++ {
+		candidate = id + "-" + strconv.Itoa(i)
+	}
+	used[candidate] = true
+	return candidate
+}
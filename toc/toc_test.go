@@ -0,0 +1,59 @@
+package toc_test
+
+import (
+	"testing"
+
+	"github.com/janmarkuslanger/ssgo/toc"
+)
+
+func TestProcess(t *testing.T) {
+	in := `<h1>Title</h1><h2 class="x">Hello <em>World</em></h2><h3>Sub</h3><h4>Deep</h4>` +
+		`<h2 id="custom">Other</h2><h3>Sub</h3><h2>!!</h2>`
+
+	out, got := toc.Process(in, toc.Options{})
+
+	wantOut := `<h1 id="title">Title</h1><h2 class="x" id="hello-world">Hello <em>World</em></h2>` +
+		`<h3 id="sub">Sub</h3><h4 id="deep">Deep</h4><h2 id="custom">Other</h2><h3 id="sub-1">Sub</h3><h2 id="section">!!</h2>`
+	if out != wantOut {
+		t.Errorf("got  %s\nwant %s", out, wantOut)
+	}
+
+	wantTOC := `<ul><li><a href="#hello-world">Hello World</a><ul><li><a href="#sub">Sub</a></li></ul></li>` +
+		`<li><a href="#custom">Other</a><ul><li><a href="#sub-1">Sub</a></li></ul></li>` +
+		`<li><a href="#section">!!</a></li></ul>`
+	if string(got.HTML()) != wantTOC {
+		t.Errorf("got  %s\nwant %s", got.HTML(), wantTOC)
+	}
+	if got[0].Level != 2 || got[0].Children[0].Text != "Sub" {
+		t.Errorf("unexpected structure: %+v", got[0])
+	}
+}
+
+func TestProcess_Levels(t *testing.T) {
+	got := toc.Extract(`<h1>A</h1><h2>B</h2><h3>C</h3>`, toc.Options{MinLevel: 1, MaxLevel: 2})
+
+	if len(got) != 1 || got[0].ID != "a" || len(got[0].Children) != 1 || got[0].Children[0].ID != "b" {
+		t.Errorf("unexpected toc: %+v", got)
+	}
+}
+
+func TestProcess_AnchorLinks(t *testing.T) {
+	opts := toc.Options{AnchorLinks: true, AnchorClass: "hash", AnchorText: "¶"}
+	want := `<h2 id="a">A <a class="hash" href="#a" aria-hidden="true">¶</a></h2>`
+
+	out, _ := toc.Process(`<h2>A</h2>`, opts)
+	if out != want {
+		t.Errorf("got %s", out)
+	}
+
+	// Processing twice does not add a second anchor.
+	if out, _ := toc.Process(out, opts); out != want {
+		t.Errorf("got %s", out)
+	}
+}
+
+func TestTOC_HTML_Empty(t *testing.T) {
+	if got := toc.TOC(nil).HTML(); got != "" {
+		t.Errorf("got %q", got)
+	}
+}
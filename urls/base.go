@@ -0,0 +1,74 @@
+package urls
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// BasePath returns the path of a base URL with a trailing slash,
+// e.g. "/docs/" for "https://example.com/docs".
+func BasePath(baseURL string) string {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "/"
+	}
+
+	p := strings.Trim(u.Path, "/")
+	if p == "" {
+		return "/"
+	}
+	return "/" + p + "/"
+}
+
+// AbsURL returns the absolute URL of p below baseURL. URLs that already
+// have a scheme or host are returned unchanged.
+func AbsURL(baseURL string, p string) string {
+	if isAbs(p) {
+		return p
+	}
+
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Host == "" {
+		return RelURL(baseURL, p)
+	}
+	return u.Scheme + "://" + u.Host + RelURL(baseURL, p)
+}
+
+// RelURL returns p as a root-relative URL prefixed with the base path.
+// URLs that already have a scheme or host are returned unchanged.
+func RelURL(baseURL string, p string) string {
+	if isAbs(p) {
+		return p
+	}
+	return BasePath(baseURL) + strings.TrimPrefix(p, "/")
+}
+
+var rootRelativeAttr = regexp.MustCompile(`(\s(?:href|src)\s*=\s*["'])(/[^"']*)`)
+
+// RewriteRootRelative prefixes root-relative href and src attributes with the
+// base path. Protocol-relative URLs and URLs already below the base path are kept.
+func RewriteRootRelative(html string, baseURL string) string {
+	base := BasePath(baseURL)
+	if base == "/" {
+		return html
+	}
+
+	return rootRelativeAttr.ReplaceAllStringFunc(html, func(m string) string {
+		parts := rootRelativeAttr.FindStringSubmatch(m)
+		link := parts[2]
+		// Links from relURL or absURL already carry the base path.
+		if strings.HasPrefix(link, "//") || strings.HasPrefix(link, base) || link+"/" == base {
+			return m
+		}
+		return parts[1] + RelURL(baseURL, link)
+	})
+}
+
+func isAbs(p string) bool {
+	if strings.HasPrefix(p, "//") {
+		return true
+	}
+	u, err := url.Parse(p)
+	return err == nil && u.Scheme != ""
+}
@@ -0,0 +1,85 @@
+package urls_test
+
+import (
+	"testing"
+
+	"github.com/janmarkuslanger/ssgo/urls"
+)
+
+func TestBasePath(t *testing.T) {
+	cases := map[string]string{
+		"":                          "/",
+		"https://example.com":       "/",
+		"https://example.com/":      "/",
+		"https://example.com/docs":  "/docs/",
+		"https://example.com/docs/": "/docs/",
+		"/docs/":                    "/docs/",
+	}
+
+	for in, want := range cases {
+		if got := urls.BasePath(in); got != want {
+			t.Errorf("BasePath(%q): got %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestAbsURL(t *testing.T) {
+	base := "https://example.com/docs/"
+
+	cases := []struct {
+		base string
+		path string
+		want string
+	}{
+		{base: base, path: "/blog/post.html", want: "https://example.com/docs/blog/post.html"},
+		{base: base, path: "blog/", want: "https://example.com/docs/blog/"},
+		{base: base, path: "/", want: "https://example.com/docs/"},
+		{base: base, path: "https://other.org/x", want: "https://other.org/x"},
+		{base: base, path: "//cdn.example.com/x.js", want: "//cdn.example.com/x.js"},
+		{base: "", path: "/blog/", want: "/blog/"},
+	}
+
+	for _, c := range cases {
+		if got := urls.AbsURL(c.base, c.path); got != c.want {
+			t.Errorf("AbsURL(%q, %q): got %q, want %q", c.base, c.path, got, c.want)
+		}
+	}
+}
+
+func TestRelURL(t *testing.T) {
+	base := "https://example.com/docs/"
+
+	cases := []struct {
+		base string
+		path string
+		want string
+	}{
+		{base: base, path: "/blog/post.html", want: "/docs/blog/post.html"},
+		{base: base, path: "style.css", want: "/docs/style.css"},
+		{base: base, path: "/docs/blog/", want: "/docs/docs/blog/"},
+		{base: base, path: "docs/intro.html", want: "/docs/docs/intro.html"},
+		{base: base, path: "mailto:a@b.c", want: "mailto:a@b.c"},
+		{base: "https://example.com", path: "/blog/", want: "/blog/"},
+	}
+
+	for _, c := range cases {
+		if got := urls.RelURL(c.base, c.path); got != c.want {
+			t.Errorf("RelURL(%q, %q): got %q, want %q", c.base, c.path, got, c.want)
+		}
+	}
+}
+
+func TestRewriteRootRelative(t *testing.T) {
+	in := `<a href="/blog/">Blog</a><img src='/img/a.png'><a href="//cdn.example.com/x">x</a>` +
+		`<a href="/docs/already/">y</a><a href="/docs">d</a><a href="relative.html">z</a><a href="https://example.com/">e</a>`
+	want := `<a href="/docs/blog/">Blog</a><img src='/docs/img/a.png'><a href="//cdn.example.com/x">x</a>` +
+		`<a href="/docs/already/">y</a><a href="/docs">d</a><a href="relative.html">z</a><a href="https://example.com/">e</a>`
+
+	if got := urls.RewriteRootRelative(in, "https://example.com/docs/"); got != want {
+		t.Errorf("unexpected output:\n%s\nexpected:\n%s", got, want)
+	}
+
+	if got := urls.RewriteRootRelative(in, "https://example.com/"); got != in {
+		t.Errorf("expected no rewrite without base path, got:\n%s", got)
+	}
+}
@@ -0,0 +1,115 @@
+package urls
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+type Style string
+
+const (
+	// Ugly writes blog/post as blog/post.html and links to /blog/post.html.
+	Ugly Style = "ugly"
+	// Pretty writes blog/post as blog/post/index.html and links to /blog/post/.
+	Pretty Style = "pretty"
+)
+
+const htmlExt = ".html"
+
+// Config is the site-wide URL style shared by the builder, writers and the
+// dev server so that links and files always agree.
+type Config struct {
+	Style Style
+	// TrailingSlash ends pretty URLs with "/". Ugly URLs never end with "/".
+	TrailingSlash bool
+}
+
+// File returns the output file path for a page path and extension.
+// An empty extension is treated as ".html", also for page paths containing
+// a dot such as blog/v1.2. The page path may be an OS path that already
+// includes the output dir.
+func (c Config) File(pagePath string, ext string) string {
+	if ext == "" {
+		ext = htmlExt
+	}
+
+	if ext != htmlExt {
+		return pagePath + ext
+	}
+	if pagePath == "" {
+		return "index" + htmlExt
+	}
+	if c.Style == Pretty && filepath.Base(pagePath) != "index" {
+		return filepath.Join(pagePath, "index"+htmlExt)
+	}
+	return pagePath + htmlExt
+}
+
+// URL returns the root-relative URL for a page path and extension.
+// An empty extension is treated like in File.
+func (c Config) URL(pagePath string, ext string) string {
+	p := clean(pagePath)
+	if ext == "" {
+		ext = htmlExt
+	}
+
+	if ext != htmlExt {
+		return "/" + p + ext
+	}
+	if c.Style != Pretty {
+		if p == "" {
+			return "/"
+		}
+		return "/" + p + htmlExt
+	}
+
+	if path.Base(p) == "index" {
+		p = strings.TrimSuffix(path.Dir(p), ".")
+	}
+	if p == "" {
+		return "/"
+	}
+	if c.TrailingSlash {
+		return "/" + p + "/"
+	}
+	return "/" + p
+}
+
+// Aliases returns URLs that should lead to the same page as URL, e.g. the
+// variant without trailing slash. The dev server redirects them.
+func (c Config) Aliases(pagePath string, ext string) []string {
+	canonical := c.URL(pagePath, ext)
+	if canonical == "/" || c.Style != Pretty || path.Ext(canonical) != "" {
+		return nil
+	}
+
+	if strings.HasSuffix(canonical, "/") {
+		return []string{strings.TrimSuffix(canonical, "/")}
+	}
+	return []string{canonical + "/"}
+}
+
+func clean(pagePath string) string {
+	return strings.Trim(path.Clean("/"+filepath.ToSlash(pagePath)), "/")
+}
+
+// Slugify lowercases s and joins its letters and digits with dashes,
+// e.g. "Hello, World!" becomes "hello-world".
+func Slugify(s string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(s) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			dash = false
+			continue
+		}
+		dash = true
+	}
+	return b.String()
+}
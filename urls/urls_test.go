@@ -0,0 +1,98 @@
+package urls_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/janmarkuslanger/ssgo/urls"
+)
+
+func TestConfig_File(t *testing.T) {
+	ugly := urls.Config{}
+	pretty := urls.Config{Style: urls.Pretty}
+
+	cases := []struct {
+		config urls.Config
+		path   string
+		ext    string
+		want   string
+	}{
+		{config: ugly, path: "blog/post", want: "blog/post.html"},
+		{config: ugly, path: "blog/post", ext: ".html", want: "blog/post.html"},
+		{config: ugly, path: "api/posts", ext: ".json", want: "api/posts.json"},
+		{config: ugly, path: "feed", ext: ".xml", want: "feed.xml"},
+		{config: ugly, path: "blog/v1.2", want: "blog/v1.2.html"},
+		{config: ugly, path: "", want: "index.html"},
+		{config: pretty, path: "blog/post", want: "blog/post/index.html"},
+		{config: pretty, path: "blog/index", want: "blog/index.html"},
+		{config: pretty, path: "index", want: "index.html"},
+		{config: pretty, path: "api/posts", ext: ".json", want: "api/posts.json"},
+		{config: pretty, path: "robots", ext: ".txt", want: "robots.txt"},
+		{config: pretty, path: "about.me", want: "about.me/index.html"},
+	}
+
+	for _, c := range cases {
+		got := c.config.File(filepath.FromSlash(c.path), c.ext)
+		if got != filepath.FromSlash(c.want) {
+			t.Errorf("File(%q, %q) in %q: got %q, want %q", c.path, c.ext, c.config.Style, got, c.want)
+		}
+	}
+}
+
+func TestConfig_URL(t *testing.T) {
+	ugly := urls.Config{Style: urls.Ugly}
+	pretty := urls.Config{Style: urls.Pretty}
+	slash := urls.Config{Style: urls.Pretty, TrailingSlash: true}
+
+	cases := []struct {
+		config urls.Config
+		path   string
+		ext    string
+		want   string
+	}{
+		{config: ugly, path: "blog/post", want: "/blog/post.html"},
+		{config: ugly, path: "/blog/post", want: "/blog/post.html"},
+		{config: ugly, path: "", want: "/"},
+		{config: ugly, path: "/", want: "/"},
+		{config: ugly, path: "api/posts", ext: ".json", want: "/api/posts.json"},
+		{config: ugly, path: "feed", ext: ".xml", want: "/feed.xml"},
+		{config: ugly, path: "blog/v1.2", want: "/blog/v1.2.html"},
+		{config: pretty, path: "blog/post", want: "/blog/post"},
+		{config: pretty, path: "blog/index", want: "/blog"},
+		{config: pretty, path: "index", want: "/"},
+		{config: slash, path: "blog/post", want: "/blog/post/"},
+		{config: slash, path: "blog/post/", want: "/blog/post/"},
+		{config: slash, path: "blog/index", want: "/blog/"},
+		{config: slash, path: "index", want: "/"},
+		{config: slash, path: "api/posts", ext: ".json", want: "/api/posts.json"},
+	}
+
+	for _, c := range cases {
+		if got := c.config.URL(c.path, c.ext); got != c.want {
+			t.Errorf("URL(%q, %q) in %+v: got %q, want %q", c.path, c.ext, c.config, got, c.want)
+		}
+	}
+}
+
+func TestConfig_Aliases(t *testing.T) {
+	cases := []struct {
+		config urls.Config
+		path   string
+		ext    string
+		want   []string
+	}{
+		{config: urls.Config{}, path: "blog/post", want: nil},
+		{config: urls.Config{Style: urls.Pretty}, path: "blog/post", want: []string{"/blog/post/"}},
+		{config: urls.Config{Style: urls.Pretty, TrailingSlash: true}, path: "blog/post", want: []string{"/blog/post"}},
+		{config: urls.Config{Style: urls.Pretty, TrailingSlash: true}, path: "index", want: nil},
+		{config: urls.Config{Style: urls.Pretty}, path: "api/posts", ext: ".json", want: nil},
+	}
+
+	for _, c := range cases {
+		got := c.config.Aliases(c.path, c.ext)
+		if strings.Join(got, ",") != strings.Join(c.want, ",") {
+			t.Errorf("Aliases(%q, %q) in %+v: got %v, want %v", c.path, c.ext, c.config, got, c.want)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package writer
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/janmarkuslanger/ssgo/urls"
 )
 
 const (
@@ -15,16 +18,76 @@ func NewFileWriter() *FileWriter {
 	return &FileWriter{}
 }
 
-type FileWriter struct{}
+type FileWriter struct {
+	// URLs decides how page paths are written, e.g. blog/post.html or
+	// blog/post/index.html.
+	URLs urls.Config
+}
+
+// knownExtensions are kept by Write. Other paths are pages, also when they
+// contain a dot such as blog/v1.2.
+var knownExtensions = map[string]bool{
+	".html": true, ".htm": true, ".json": true, ".xml": true, ".txt": true, ".rss": true, ".atom": true,
+	".css": true, ".js": true, ".mjs": true, ".map": true, ".svg": true, ".csv": true, ".md": true,
+	".webmanifest": true, ".ics": true, ".yaml": true, ".yml": true, ".toml": true,
+}
 
+// Write writes a page. Paths with a known extension such as api/posts.json
+// are written as they are, others get .html following URLs. Use WriteExact
+// for files with other extensions.
 func (w *FileWriter) Write(path string, content string) error {
+	if !knownExtensions[strings.ToLower(filepath.Ext(path))] {
+		path = w.URLs.File(path, ".html")
+	}
+
+	return w.WriteExact(path, content)
+}
+
+func (w *FileWriter) WriteExact(path string, content string) error {
 	if err := os.MkdirAll(filepath.Dir(path), DirPerm); err != nil {
 		return err
 	}
 
-	if !strings.HasSuffix(path, ".html") {
-		path += ".html"
+	return os.WriteFile(path, []byte(content), FilePerm)
+}
+
+// Create opens the file at exactly path for writing. The content goes to a
+// temporary file in the same directory that replaces path on Close, so a
+// failed render can Abort without touching the previous file.
+func (w *FileWriter) Create(path string) (io.WriteCloser, error) {
+	if err := os.MkdirAll(filepath.Dir(path), DirPerm); err != nil {
+		return nil, err
 	}
 
-	return os.WriteFile(path, []byte(content), FilePerm)
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	return &tempFile{File: f, path: path}, nil
+}
+
+type tempFile struct {
+	*os.File
+	path string
+}
+
+func (f *tempFile) Close() error {
+	if err := f.File.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Chmod(f.Name(), FilePerm); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), f.path); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return nil
+}
+
+func (f *tempFile) Abort() error {
+	f.File.Close()
+	return os.Remove(f.Name())
 }
@@ -1,10 +1,12 @@
 package writer_test
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/janmarkuslanger/ssgo/urls"
 	"github.com/janmarkuslanger/ssgo/writer"
 )
 
@@ -79,3 +81,123 @@ func TestFileWriter_Write_Err(t *testing.T) {
 	}
 	t.Logf("got expected error: %v", err)
 }
+
+func TestFileWriter_Write_DottedPath(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writer := writer.FileWriter{}
+	path := filepath.Join(tmpDir, "blog", "v1.2")
+	content := "<h1>Release</h1>"
+
+	err := writer.Write(path, content)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path + ".html")
+	if err != nil {
+		t.Fatalf("file was not created: %v", err)
+	}
+
+	if string(data) != content {
+		t.Errorf("unexpected content: got %q, want %q", data, content)
+	}
+}
+
+func TestFileWriter_WriteExact(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writer := writer.FileWriter{}
+	path := filepath.Join(tmpDir, "_redirects")
+
+	if err := writer.WriteExact(path, "/a /b 301"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("file was not created at exact path: %v", err)
+	}
+}
+
+func TestFileWriter_Write_PrettyURLs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writer := writer.FileWriter{URLs: urls.Config{Style: urls.Pretty}}
+	path := filepath.Join(tmpDir, "blog", "post")
+
+	if err := writer.Write(path, "<h1>Hello</h1>"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(path, "index.html")); err != nil {
+		t.Fatalf("file was not created: %v", err)
+	}
+}
+
+func TestFileWriter_Create(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writer := writer.FileWriter{}
+	path := filepath.Join(tmpDir, "sitemap.xml")
+
+	w, err := writer.Create(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := io.WriteString(w, "<urlset/>"); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected close error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "<urlset/>" {
+		t.Errorf("unexpected content %q, err %v", data, err)
+	}
+}
+
+func TestFileWriter_Create_Abort(t *testing.T) {
+	tmpDir := t.TempDir()
+	path := filepath.Join(tmpDir, "a.html")
+	if err := os.WriteFile(path, []byte("previous"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fw := writer.FileWriter{}
+	w, err := fw.Create(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := io.WriteString(w, "half a pa"); err != nil {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if err := w.(writer.Aborter).Abort(); err != nil {
+		t.Fatalf("unexpected abort error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "previous" {
+		t.Errorf("previous file was changed: %q, err %v", data, err)
+	}
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 1 {
+		t.Errorf("temporary file was left behind: %v", entries)
+	}
+}
+
+func TestFileWriter_Write_KnownExtension(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	writer := writer.FileWriter{}
+	path := filepath.Join(tmpDir, "api", "posts.json")
+
+	if err := writer.Write(path, `{"posts":[]}`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != `{"posts":[]}` {
+		t.Errorf("unexpected content %q, err %v", data, err)
+	}
+}
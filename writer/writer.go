@@ -1,5 +1,26 @@
 package writer
 
+import "io"
+
 type Writer interface {
 	Write(path string, content string) error
 }
+
+// ExactWriter is implemented by writers that can write a file at exactly the
+// given path, without page conventions such as the .html suffix.
+type ExactWriter interface {
+	WriteExact(path string, content string) error
+}
+
+// StreamWriter is implemented by writers that hand out a destination for
+// streaming output, so pages do not have to be buffered as a string. Like
+// WriteExact, the path is used as given. The caller must close it.
+type StreamWriter interface {
+	Create(path string) (io.WriteCloser, error)
+}
+
+// Aborter is implemented by destinations from StreamWriter.Create that can
+// discard what was written, e.g. after a failed render. Abort replaces Close.
+type Aborter interface {
+	Abort() error
+}